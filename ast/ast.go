@@ -34,6 +34,10 @@ type Expression interface {
 	expressionNode()
 }
 
+type Pattern interface {
+	patternNode()
+}
+
 type Statement interface {
 	statementNode()
 }
@@ -59,21 +63,89 @@ type UnaryExpression struct {
 	Operator string
 }
 
+// TypeConversion converts a value to a builtin type, e.g. int(c).
+type TypeConversion struct {
+	BaseNode
+	Token token.Token // The type's token
+	Type  *TypeLiteral
+	Value Expression
+}
+
 type ParenExpression struct {
 	Expression Expression
 }
 
+// RangeExpression is the integers from Start up to End, including End when
+// Inclusive, e.g. 0..10 or 1..=10.
+type RangeExpression struct {
+	BaseNode
+	Token     token.Token // The .. or ..= token
+	Start     Expression
+	End       Expression
+	Inclusive bool
+}
+
+// TupleExpression groups values, e.g. (x, y), which can be matched on with
+// tuple patterns.
+type TupleExpression struct {
+	BaseNode
+	Items []Expression
+}
+
+// MemberExpression accesses a field of a value, e.g. user.name.
+type MemberExpression struct {
+	Object   Expression
+	Property *Identifier
+}
+
+// IndexExpression is an element of a slice or map, e.g. xs[0].
+type IndexExpression struct {
+	BaseNode
+	Object Expression
+	Index  Expression
+}
+
+// CallExpression calls a function. Arguments are positional or labeled with
+// the name of a parameter, see LabeledArgument.
 type CallExpression struct {
+	Token     token.Token // The '('
 	Function  Expression
 	Arguments []Expression
 }
 
+// LabeledArgument is an argument passed to the parameter with the name of
+// its label, e.g. id: x in f(id: x). Punned arguments pass the variable with
+// the name of the label, e.g. f(id:) or f(~id) for f(id: id).
+type LabeledArgument struct {
+	BaseNode
+	Token  token.Token
+	Label  string
+	Value  Expression
+	Punned bool
+}
+
+// LetStatement declares a variable. Only mutable variables, declared with
+// let mut, may be assigned after their declaration. The type may be given
+// explicitly, e.g. let x: int = 1, and is required when there is no value.
+//
 // TODO: explore let assert ... syntax similar to gleam
 type LetStatement struct {
 	BaseNode
-	Token token.Token
-	Name  *Identifier
-	Value Expression
+	Doc     string
+	Token   token.Token
+	Name    *Identifier
+	Mutable bool
+	Type    Type
+	Value   Expression
+}
+
+// ExpressionStatement evaluates an expression for its effects, e.g. a call.
+// As the last statement of a block it is the value of the block, e.g. the
+// result of fn double(x: int) int { x * 2 }.
+type ExpressionStatement struct {
+	BaseNode
+	Token      token.Token // First token of the expression
+	Expression Expression
 }
 
 type BlockStatement struct {
@@ -90,26 +162,122 @@ type If struct {
 	// TODO: Else can be a block or another if condition
 }
 
+// Match evaluates the first arm whose pattern matches the subject, e.g.
+//
+//	match shape {
+//		Shape.Circle(r) => r * r,
+//		_ => 0,
+//	}
+type Match struct {
+	BaseNode
+	Token   token.Token
+	Subject Expression
+	Arms    []*MatchArm
+}
+
+// MatchArm is a pattern with the value or block evaluated when it matches.
+// Only one of Value and Body is set.
+type MatchArm struct {
+	BaseNode
+	Token   token.Token // First token of the pattern
+	Pattern Pattern
+	Value   Expression
+	Body    *BlockStatement
+}
+
+// WildcardPattern matches any value, written _.
+type WildcardPattern struct{ BaseNode }
+
+// BindingPattern matches any value and binds it to a name. Bare names of
+// enum members and union variants, e.g. None, are resolved to variant
+// patterns by the checker and compiler.
+type BindingPattern struct {
+	BaseNode
+	Token token.Token
+	Name  string
+}
+
+// LiteralPattern matches values equal to a literal, e.g. 1, -1.5 or "a".
+type LiteralPattern struct {
+	BaseNode
+	Value Expression
+}
+
+// TuplePattern matches each item of a tuple, e.g. (0, _).
+type TuplePattern struct {
+	BaseNode
+	Items []Pattern
+}
+
+// VariantPattern matches an enum member or union variant, e.g. Color.Red or
+// Option.Some(x). Variants may omit the type, e.g. Some(x), and a bare name
+// is parsed as a BindingPattern. Payload is nil for variants without one.
+type VariantPattern struct {
+	BaseNode
+	Token   token.Token
+	Type    string
+	Name    string
+	Payload Pattern
+}
+
 type Loop struct {
 	BaseNode
 	Body *BlockStatement
 }
 
+// For repeats its body while the condition holds. The init and post
+// statements of C-style loops run before the loop and after each iteration,
+// e.g. for i = 0; i < 10; i = i + 1 {}.
 type For struct {
 	BaseNode
+	Init      Node
 	Condition Expression
+	Post      Node
 	Body      *BlockStatement
 }
 
+// ForIn runs its body for each item of a slice, map or range, e.g.
+// for x in xs {}, for key, value in m {} or for i in 0..10 {}. Key holds
+// the index or map key when two names are given.
+type ForIn struct {
+	BaseNode
+	Key      *Identifier
+	Item     *Identifier
+	Iterable Expression
+	Body     *BlockStatement
+}
+
+// AssignStatement assigns a new value to a variable, field or index, e.g.
+// x = 1, p.x += 1 or xs[i] = x. Operator is "=" or a compound operator such
+// as "+=".
+type AssignStatement struct {
+	BaseNode
+	Token    token.Token
+	Target   Expression
+	Operator string
+	Value    Expression
+}
+
+// Parameter is a parameter of a function. Optional parameters, e.g.
+// id?: string, and parameters with a default value, e.g. size: int = 1, may
+// be left out of calls. Optional parameters without a default value receive
+// the zero value of their type.
 type Parameter struct {
 	BaseNode
-	Name    string
-	Type    Type
-	Default *Expression
+	Token    token.Token
+	Name     string
+	Type     Type
+	Optional bool
+	Default  Expression
+
+	// Variadic parameters take any number of arguments of their type, e.g.
+	// items: ...string
+	Variadic bool
 }
 
 type TypeIdentifier struct {
 	BaseNode
+	Token      token.Token
 	Name       string
 	Parameters []*TypeParameter
 }
@@ -124,14 +292,23 @@ type TypeLiteral struct {
 	Type string
 }
 
+// FuncType is the type of a function, e.g. fn(int, string) bool.
+type FuncType struct {
+	BaseNode
+	Params     []Type
+	ReturnType Type
+}
+
 type Enum struct {
 	BaseNode
+	Doc     string
 	Name    string
 	Members []*EnumMember
 }
 
 type EnumMember struct {
 	BaseNode
+	Doc      string
 	Name     string
 	IntValue int64
 	Value    Expression
@@ -139,6 +316,8 @@ type EnumMember struct {
 
 type Union struct {
 	BaseNode
+	Token      token.Token
+	Doc        string
 	Name       string
 	Fields     []*UnionField
 	Parameters []*TypeParameter
@@ -146,17 +325,35 @@ type Union struct {
 
 type UnionField struct {
 	BaseNode
+	Doc  string
 	Name string
 	Type Type // literal type | union type | struct body | type ref with or without parameters
 }
 
 type Func struct {
 	BaseNode
+	Token      token.Token
+	Doc        string
 	Name       string
 	Params     []*Parameter
 	TypeParams []*TypeParameter
 	Body       *BlockStatement
 	ReturnType Type
+
+	// Receiver is the type of methods, which are declared in the body of a
+	// struct or for a type, e.g. fn area() for Rect int, and receive their
+	// value as self.
+	Receiver *TypeIdentifier
+}
+
+// FuncLiteral is an anonymous function, which may use the variables of the
+// scope it is declared in, e.g. fn(n: int) int { n * factor }.
+type FuncLiteral struct {
+	BaseNode
+	Token      token.Token
+	Params     []*Parameter
+	ReturnType Type
+	Body       *BlockStatement
 }
 
 type ReturnStatement struct {
@@ -173,11 +370,73 @@ type IntegerLiteral struct {
 	Signed bool
 }
 
+type FloatLiteral struct {
+	BaseNode
+	Value  float64
+	Signed bool
+}
+
 type StringLiteral struct {
 	BaseNode
 	Value string
 }
 
+type CharLiteral struct {
+	BaseNode
+	Value rune
+}
+
+// TemplateLiteral is a backtick string containing interpolations. Text
+// between interpolations is held as StringLiteral parts.
+type TemplateLiteral struct {
+	BaseNode
+	Parts []Expression
+}
+
+// Element is markup, e.g. <div id="app">Hello, {name}</div>. Fragments
+// (<>...</>) have no name. Children are text as StringLiteral, other elements
+// and expressions. Key identifies an element among the items of a list and
+// is not an attribute, e.g. <li key={item.id}>.
+type Element struct {
+	BaseNode
+	Token      token.Token // The < token
+	Name       string
+	Key        Expression
+	Attributes []*Attribute
+	Children   []Expression
+}
+
+// Attribute is a named element attribute, or a spread of the attributes held
+// in Value, e.g. {...props}. Value is nil for attributes given without a
+// value, e.g. <input disabled />.
+type Attribute struct {
+	BaseNode
+	Name   string
+	Value  Expression
+	Spread bool
+}
+
+// ElementIf is an if within element children, where each branch holds
+// element children, e.g. {if ok { Saved } else { <Error /> }}. Else is an
+// ElementIf for else if, or a fragment.
+type ElementIf struct {
+	BaseNode
+	Condition Expression
+	Then      []Expression
+	Else      Expression
+}
+
+// ElementFor renders its body for each item of a collection within element
+// children, e.g. {for item in items { <li>{item}</li> }}. Key holds the index
+// or map key when two names are given.
+type ElementFor struct {
+	BaseNode
+	Key      *Identifier
+	Item     *Identifier
+	Iterable Expression
+	Body     []Expression
+}
+
 type Boolean struct {
 	BaseNode
 	Value bool
@@ -190,9 +449,20 @@ type Tuple struct {
 
 type Struct struct {
 	BaseNode
-	Name   string
-	Params []*TypeParameter
-	Fields []*StructField
+	Doc     string
+	Name    string
+	Params  []*TypeParameter
+	Fields  []*StructField
+	Methods []*Func
+}
+
+// Interface is a set of methods, satisfied by the structs which declare all
+// of them. Its methods have no body or receiver.
+type Interface struct {
+	BaseNode
+	Doc     string
+	Name    string
+	Methods []*Func
 }
 
 type StructBody struct {
@@ -202,6 +472,7 @@ type StructBody struct {
 
 type StructField struct {
 	BaseNode
+	Doc  string
 	Name string
 	Type Type
 }
@@ -215,17 +486,39 @@ type TupleType struct {
 func (n TypeIdentifier) typeNode() {}
 func (n TypeLiteral) typeNode()    {}
 func (n StructBody) typeNode()     {}
+func (n FuncType) typeNode()       {}
 
 // Denote expression nodes
 func (e BinaryExpression) expressionNode() {}
 func (e UnaryExpression) expressionNode()  {}
 func (e ParenExpression) expressionNode()  {}
+func (e TupleExpression) expressionNode()  {}
+func (e RangeExpression) expressionNode()  {}
+func (e Match) expressionNode()            {}
 func (e CallExpression) expressionNode()   {}
+func (e FuncLiteral) expressionNode()      {}
+func (e LabeledArgument) expressionNode()  {}
+func (e MemberExpression) expressionNode() {}
+func (e IndexExpression) expressionNode()  {}
 func (e IntegerLiteral) expressionNode()   {}
+func (e FloatLiteral) expressionNode()     {}
 func (e StringLiteral) expressionNode()    {}
+func (e TemplateLiteral) expressionNode()  {}
+func (e CharLiteral) expressionNode()      {}
+func (e TypeConversion) expressionNode()   {}
+func (e Element) expressionNode()          {}
+func (e ElementIf) expressionNode()        {}
+func (e ElementFor) expressionNode()       {}
 func (e Boolean) expressionNode()          {}
 func (e Identifier) expressionNode()       {}
 
+// Denote pattern nodes
+func (p WildcardPattern) patternNode() {}
+func (p BindingPattern) patternNode()  {}
+func (p LiteralPattern) patternNode()  {}
+func (p TuplePattern) patternNode()    {}
+func (p VariantPattern) patternNode()  {}
+
 // Denote alternative nodes (those that can be chained with if)
 func (n If) alternativeNode()             {}
 func (n BlockStatement) alternativeNode() {}
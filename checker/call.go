@@ -0,0 +1,98 @@
+package checker
+
+import (
+	"fmt"
+	"gloss/ast"
+	"strings"
+)
+
+// checkArguments resolves the arguments of a call to a function declared in
+// the file against its parameters. Positional arguments are passed to the
+// parameters in order, and labeled arguments to the parameter of the same
+// name, e.g. f(1, id: x). Optional parameters and parameters with a default
+// value may be left out, and a variadic parameter takes the remaining
+// positional arguments. Arguments must have the type of their parameter.
+// Calls to other functions, e.g. closures and methods, cannot be resolved,
+// so their arguments may not be labeled.
+func (c *Checker) checkArguments(call *ast.CallExpression) {
+	fn := c.funcOf(call)
+	if fn == nil {
+		for _, arg := range call.Arguments {
+			if la, ok := arg.(*ast.LabeledArgument); ok {
+				c.Diagnostics.Error(la.Token, fmt.Sprintf("Cannot label argument %s of an undeclared function", la.Label))
+			}
+		}
+		return
+	}
+
+	given := map[string]bool{}
+	labeled := false
+	next := 0
+	for _, arg := range call.Arguments {
+		if la, ok := arg.(*ast.LabeledArgument); ok {
+			labeled = true
+			switch p := param(fn, la.Label); {
+			case p == nil:
+				c.Diagnostics.Error(la.Token, fmt.Sprintf("%s has no parameter named %s", fn.Name, la.Label))
+			case given[la.Label]:
+				c.Diagnostics.Error(la.Token, fmt.Sprintf("Duplicate argument %s in call to %s", la.Label, fn.Name))
+			case p.Variadic:
+				c.Diagnostics.Error(la.Token, fmt.Sprintf("Variadic argument %s cannot be labeled in call to %s", la.Label, fn.Name))
+			default:
+				c.checkAssignable(la.Token, la.Value, typeName(p.Type, fn.TypeParams))
+			}
+			given[la.Label] = true
+			continue
+		}
+
+		switch {
+		case labeled:
+			c.Diagnostics.Error(call.Token, fmt.Sprintf("Positional arguments must come before labeled arguments in call to %s", fn.Name))
+			return
+		case next == len(fn.Params):
+			c.Diagnostics.Error(call.Token, fmt.Sprintf("Too many arguments in call to %s", fn.Name))
+			return
+		}
+
+		c.checkAssignable(call.Token, arg, typeName(fn.Params[next].Type, fn.TypeParams))
+		if fn.Params[next].Variadic {
+			// The remaining arguments are passed to the variadic parameter
+			continue
+		}
+		given[fn.Params[next].Name] = true
+		next++
+	}
+
+	var missing []string
+	for _, p := range fn.Params {
+		if !given[p.Name] && !p.Optional && p.Default == nil && !p.Variadic {
+			missing = append(missing, p.Name)
+		}
+	}
+	switch len(missing) {
+	case 0:
+	case 1:
+		c.Diagnostics.Error(call.Token, fmt.Sprintf("Missing argument %s in call to %s", missing[0], fn.Name))
+	default:
+		c.Diagnostics.Error(call.Token, fmt.Sprintf("Missing arguments %s in call to %s", strings.Join(missing, ", "), fn.Name))
+	}
+}
+
+// funcOf returns the function declared in the file which is called, or nil.
+func (c *Checker) funcOf(call *ast.CallExpression) *ast.Func {
+	id, ok := call.Function.(*ast.Identifier)
+	if !ok || c.lookup(id.Name) != nil {
+		return nil
+	}
+	return c.funcs[id.Name]
+}
+
+// param returns the parameter of a function with the given name.
+func param(fn *ast.Func, name string) *ast.Parameter {
+	for _, p := range fn.Params {
+		if p.Name == name {
+			return p
+		}
+	}
+	return nil
+}
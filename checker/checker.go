@@ -0,0 +1,411 @@
+// Package checker analyses parsed source files, reporting the problems which
+// are not apparent from the syntax alone, e.g. a match which does not cover
+// every variant of a union.
+package checker
+
+import (
+	"fmt"
+	"gloss/ast"
+	"gloss/diagnostic"
+	"slices"
+)
+
+type Checker struct {
+	Diagnostics *diagnostic.MessageList
+
+	// Declarations of the file by name
+	enums      map[string]*ast.Enum
+	unions     map[string]*ast.Union
+	structs    map[string]*ast.Struct
+	interfaces map[string]*ast.Interface
+	funcs      map[string]*ast.Func
+
+	// Methods of structs by the name of the struct and the method
+	methods map[string]map[string]*ast.Func
+
+	// Variables visible at the node being checked
+	scope *scope
+
+	// Type parameters of the function being checked
+	typeParams []*ast.TypeParameter
+}
+
+func New(diagnostics *diagnostic.MessageList) *Checker {
+	return &Checker{
+		Diagnostics: diagnostics,
+		enums:       map[string]*ast.Enum{},
+		unions:      map[string]*ast.Union{},
+		structs:     map[string]*ast.Struct{},
+		interfaces:  map[string]*ast.Interface{},
+		funcs:       map[string]*ast.Func{},
+		methods:     map[string]map[string]*ast.Func{},
+	}
+}
+
+// Check analyses a file, reporting problems to the checker's diagnostics.
+func (c *Checker) Check(file *ast.SourceFile) {
+	for _, node := range file.Declarations {
+		switch n := node.(type) {
+		case *ast.Enum:
+			c.enums[n.Name] = n
+		case *ast.Union:
+			c.unions[n.Name] = n
+			if len(n.Parameters) > 0 {
+				// Constructing and matching variants requires their type
+				// arguments in Go, which are not known
+				c.Diagnostics.Error(n.Token, fmt.Sprintf("Union %s cannot have type parameters", n.Name))
+			}
+		case *ast.Struct:
+			c.structs[n.Name] = n
+		case *ast.Interface:
+			c.interfaces[n.Name] = n
+		case *ast.Func:
+			if n.Receiver == nil {
+				c.funcs[n.Name] = n
+			}
+		}
+	}
+	c.declareMethods(file)
+
+	c.openScope()
+	for _, node := range file.Declarations {
+		c.checkNode(node)
+	}
+	c.closeScope()
+}
+
+func (c *Checker) checkNode(node ast.Node) {
+	switch n := node.(type) {
+	case *ast.Struct:
+		for _, method := range n.Methods {
+			c.checkNode(method)
+		}
+	case *ast.Interface:
+		c.checkInterface(n)
+	case *ast.Func:
+		c.typeParams = typeParams(n)
+		defer func() { c.typeParams = nil }()
+
+		c.openScope()
+		if n.Receiver != nil {
+			c.checkRequiredParams(n.Params, "a method")
+			c.declare(selfVar, parameter, n.Receiver.Name)
+		}
+		c.checkParams(n.Params)
+		if n.Body != nil {
+			c.checkBlock(n.Body, n.ReturnType != nil)
+		}
+		c.closeScope()
+	case *ast.LetStatement:
+		c.checkLet(n)
+	case *ast.AssignStatement:
+		c.checkAssignment(n)
+	case *ast.ReturnStatement:
+		if m, ok := n.Value.(*ast.Match); ok {
+			c.checkMatch(m, true)
+			break
+		}
+		c.checkExpression(n.Value)
+	case *ast.ExpressionStatement:
+		c.checkExpression(n.Expression)
+	case *ast.If:
+		c.checkIf(n)
+	case *ast.Loop:
+		c.checkBlock(n.Body, false)
+	case *ast.For:
+		c.openScope()
+		c.checkNode(n.Init)
+		c.checkExpression(n.Condition)
+		c.checkNode(n.Post)
+		c.checkBlock(n.Body, false)
+		c.closeScope()
+	case *ast.ForIn:
+		c.checkIterable(n.Iterable)
+		c.openScope()
+		c.declareLoopVariables(n.Key, n.Item, n.Iterable)
+		c.checkBlock(n.Body, false)
+		c.closeScope()
+	case *ast.Match:
+		c.checkMatch(n, false)
+	}
+}
+
+// checkParams declares the parameters of a function in the current scope,
+// reporting parameters which share a name and default values which do not
+// have the type of their parameter.
+func (c *Checker) checkParams(params []*ast.Parameter) {
+	for _, param := range params {
+		if c.scope.vars[param.Name] != nil {
+			c.Diagnostics.Error(param.Token, fmt.Sprintf("Duplicate parameter %s", param.Name))
+		}
+
+		// Variadic parameters are lists of their type
+		typ := typeName(param.Type, c.typeParams)
+		if param.Variadic {
+			typ = ""
+		}
+		c.declare(param.Name, parameter, typ)
+	}
+
+	for _, param := range params {
+		if param.Default != nil {
+			c.checkExpression(param.Default)
+			c.checkAssignable(param.Token, param.Default, typeName(param.Type, c.typeParams))
+		}
+	}
+}
+
+// checkRequiredParams reports optional parameters and parameters with a
+// default value of functions whose calls are not resolved, e.g. anonymous
+// functions, so that their parameters may not be left out.
+func (c *Checker) checkRequiredParams(params []*ast.Parameter, of string) {
+	for _, param := range params {
+		if param.Optional || param.Default != nil {
+			c.Diagnostics.Error(param.Token, fmt.Sprintf("Parameter %s of %s cannot be optional or have a default value", param.Name, of))
+		}
+	}
+}
+
+// checkFuncLiteral checks an anonymous function in a scope enclosed by the
+// scope it is declared in, so that it may use its variables.
+func (c *Checker) checkFuncLiteral(fn *ast.FuncLiteral) {
+	c.checkRequiredParams(fn.Params, "an anonymous function")
+
+	c.openScope()
+	c.checkParams(fn.Params)
+	c.checkBlock(fn.Body, fn.ReturnType != nil)
+	c.closeScope()
+}
+
+// checkBlock checks the statements of a block. When value is set the
+// trailing expression is the value of the block, e.g. the body of
+// fn double(x: int) int { x * 2 }, and other expressions which are not calls
+// are reported, as Go does not allow their value to be left unused.
+func (c *Checker) checkBlock(block *ast.BlockStatement, value bool) {
+	c.openScope()
+	for i, stmt := range block.Statements {
+		tail := value && i == len(block.Statements)-1
+		switch n := stmt.(type) {
+		case *ast.ExpressionStatement:
+			if !tail && !c.isCall(n.Expression) {
+				c.Diagnostics.Error(n.Token, "Value of expression is not used")
+			}
+			c.checkExpression(n.Expression)
+		case *ast.Match:
+			c.checkMatch(n, tail)
+		default:
+			c.checkNode(stmt)
+		}
+	}
+	c.closeScope()
+}
+
+// isCall reports whether an expression calls a function, and so may be used
+// as a statement. Union variants are constructed rather than called.
+func (c *Checker) isCall(exp ast.Expression) bool {
+	call, ok := exp.(*ast.CallExpression)
+	if !ok {
+		return false
+	}
+	switch f := call.Function.(type) {
+	case *ast.Identifier:
+		for _, u := range c.unions {
+			if slices.ContainsFunc(u.Fields, func(uf *ast.UnionField) bool { return uf.Name == f.Name }) {
+				return false
+			}
+		}
+	case *ast.MemberExpression:
+		if obj, ok := f.Object.(*ast.Identifier); ok && c.unions[obj.Name] != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// checkAssignment reports assignments to variables which are not mutable,
+// including assignments to their fields and indexes, e.g. p.x = 1 where p is
+// not declared with let mut.
+func (c *Checker) checkAssignment(node *ast.AssignStatement) {
+	c.checkExpression(node.Target)
+	c.checkExpression(node.Value)
+
+	target := node.Target
+	for {
+		switch t := target.(type) {
+		case *ast.MemberExpression:
+			target = t.Object
+			continue
+		case *ast.IndexExpression:
+			target = t.Object
+			continue
+		case *ast.Identifier:
+			c.checkMutable(t)
+			// The types of fields and items are not known
+			if v := c.lookup(t.Name); v != nil && v.typ != "" && node.Operator == "=" && t == node.Target {
+				c.checkAssignable(node.Token, node.Value, v.typ)
+			}
+		}
+		return
+	}
+}
+
+// checkLet declares a variable, reporting values which do not have its
+// explicit type. Variables without an explicit type have the type of their
+// value.
+func (c *Checker) checkLet(node *ast.LetStatement) {
+	if m, ok := node.Value.(*ast.Match); ok && node.Type != nil {
+		c.checkMatch(m, true)
+	} else {
+		c.checkExpression(node.Value)
+	}
+
+	typ := typeName(node.Type, c.typeParams)
+	if node.Type == nil {
+		typ = c.typeOf(node.Value)
+	} else if node.Value != nil {
+		c.checkAssignable(node.Name.Token, node.Value, typ)
+	}
+
+	kind := variable
+	if node.Mutable {
+		kind = mutableVariable
+	} else if node.Value == nil {
+		// Variables which are not mutable are only given their value by let
+		c.Diagnostics.Error(node.Name.Token, fmt.Sprintf("Variable %s has no value and is not mutable", node.Name.Name))
+	}
+	c.declare(node.Name.Name, kind, typ)
+}
+
+func (c *Checker) checkMutable(id *ast.Identifier) {
+	v := c.lookup(id.Name)
+	switch {
+	case v == nil, v.kind == mutableVariable:
+	case v.kind == variable:
+		c.Diagnostics.Error(id.Token, fmt.Sprintf("Cannot assign to %s, it is not mutable", id.Name))
+	default:
+		c.Diagnostics.Error(id.Token, fmt.Sprintf("Cannot assign to %s %s", v.kind, id.Name))
+	}
+}
+
+func (c *Checker) checkIf(node *ast.If) {
+	c.checkExpression(node.Condition)
+	c.checkBlock(node.Then, false)
+	switch e := node.Else.(type) {
+	case *ast.If:
+		c.checkIf(e)
+	case *ast.BlockStatement:
+		c.checkBlock(e, false)
+	}
+}
+
+func (c *Checker) checkExpression(exp ast.Expression) {
+	switch e := exp.(type) {
+	case *ast.BinaryExpression:
+		c.checkExpression(e.Left)
+		c.checkExpression(e.Right)
+	case *ast.UnaryExpression:
+		c.checkExpression(e.Right)
+	case *ast.ParenExpression:
+		c.checkExpression(e.Expression)
+	case *ast.RangeExpression:
+		// Ranges are compiled to the bounds of loops, and are not values
+		c.Diagnostics.Error(e.Token, "Cannot use range as a value")
+		c.checkIterable(e)
+	case *ast.TupleExpression:
+		c.checkExpressions(e.Items)
+	case *ast.CallExpression:
+		c.checkExpression(e.Function)
+		c.checkExpressions(e.Arguments)
+		c.checkArguments(e)
+	case *ast.LabeledArgument:
+		c.checkExpression(e.Value)
+	case *ast.FuncLiteral:
+		c.checkFuncLiteral(e)
+	case *ast.MemberExpression:
+		c.checkExpression(e.Object)
+	case *ast.IndexExpression:
+		c.checkExpression(e.Object)
+		c.checkExpression(e.Index)
+	case *ast.TypeConversion:
+		c.checkExpression(e.Value)
+		c.checkConversion(e)
+	case *ast.TemplateLiteral:
+		c.checkExpressions(e.Parts)
+	case *ast.Match:
+		// Matches used as values are compiled to functions, which return the
+		// type of the match
+		if c.matchType(e) == "" {
+			c.Diagnostics.Error(e.Token, "Cannot infer the type of match")
+		}
+		c.checkMatch(e, true)
+	case *ast.Element:
+		if e.Key != nil {
+			c.Diagnostics.Error(e.Token, "Key must be on the only element of a for loop body")
+		}
+		c.checkElement(e)
+	case *ast.ElementIf:
+		c.checkExpression(e.Condition)
+		c.checkExpressions(e.Then)
+		c.checkExpression(e.Else)
+	case *ast.ElementFor:
+		c.checkIterable(e.Iterable)
+		c.openScope()
+		c.declareLoopVariables(e.Key, e.Item, e.Iterable)
+		if el := keyed(e); el != nil {
+			c.checkElement(el)
+		} else {
+			c.checkExpressions(e.Body)
+		}
+		c.closeScope()
+	}
+}
+
+// checkElement checks the key, attributes and children of an element.
+func (c *Checker) checkElement(el *ast.Element) {
+	c.checkExpression(el.Key)
+	for _, attr := range el.Attributes {
+		c.checkExpression(attr.Value)
+	}
+	c.checkExpressions(el.Children)
+}
+
+// keyed returns the element with a key which is the body of a for loop
+// within element children, or nil. Keys of other elements are not compiled.
+func keyed(node *ast.ElementFor) *ast.Element {
+	if len(node.Body) != 1 {
+		return nil
+	}
+	if el, ok := node.Body[0].(*ast.Element); ok && el.Key != nil {
+		return el
+	}
+	return nil
+}
+
+func (c *Checker) checkExpressions(exps []ast.Expression) {
+	for _, exp := range exps {
+		c.checkExpression(exp)
+	}
+}
+
+// checkIterable checks the collection or range iterated by a loop.
+func (c *Checker) checkIterable(iterable ast.Expression) {
+	if r, ok := iterable.(*ast.RangeExpression); ok {
+		c.checkExpression(r.Start)
+		c.checkExpression(r.End)
+		return
+	}
+	c.checkExpression(iterable)
+}
+
+func (c *Checker) declareLoopVariables(key, item *ast.Identifier, iterable ast.Expression) {
+	if key != nil {
+		c.declare(key.Name, loopVariable, "")
+	}
+
+	// The items of ranges are integers
+	var typ string
+	if _, ok := iterable.(*ast.RangeExpression); ok {
+		typ = "int"
+	}
+	c.declare(item.Name, loopVariable, typ)
+}
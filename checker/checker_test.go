@@ -0,0 +1,653 @@
+package checker
+
+import (
+	"fmt"
+	"gloss/lexer"
+	"gloss/parser"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func assertDiagnostics(t *testing.T, input string, want []string) {
+	t.Helper()
+	p := parser.NewParser(lexer.New([]byte(input)))
+	source := p.Parse()
+	New(p.Diagnostics).Check(&source)
+
+	var got []string
+	for _, msg := range p.Diagnostics.Messages() {
+		got = append(got, fmt.Sprintf("%d:%d: %s", msg.Line, msg.Column, msg.Text))
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("checker.Check() diagnostics mismatch (-want +got):\nInput:%s\n%s", input, diff)
+	}
+}
+
+func TestCheckMatch_Exhaustive(t *testing.T) {
+	input := `
+enum Color { Red, Green }
+union Shape { Circle(int), Rect({ w: int, h: int }), Empty }
+
+fn name(c: Color) string {
+	return match c {
+		Color.Red => "red",
+		Color.Green => "green",
+	}
+}
+
+fn area(s: Shape) int {
+	return match s {
+		Shape.Circle(r) => r * r,
+		Shape.Rect(w, h) => w * h,
+		Shape.Empty => 0,
+	}
+}
+
+fn sign(n: int) int {
+	return match n {
+		0 => 0,
+		_ => 1,
+	}
+}
+
+fn both(a: bool, b: bool) int {
+	return match (a, b) {
+		(true, _) => 1,
+		(false, true) => 2,
+		(false, false) => 3,
+	}
+}
+`
+	assertDiagnostics(t, input, nil)
+}
+
+func TestCheckMatch_MissingVariants(t *testing.T) {
+	input := `
+enum Color { Red, Green, Blue }
+union Shape { Circle(int), Rect({ w: int, h: int }), Empty }
+
+fn name(c: Color) string {
+	return match c {
+		Color.Red => "red",
+	}
+}
+
+fn area(s: Shape) int {
+	return match s {
+		Shape.Circle(r) => r * r,
+	}
+}
+
+fn count(n: int) string {
+	return match n {
+		1 => "one",
+		2 => "two",
+	}
+}
+
+fn both(a: bool, b: bool) int {
+	return match (a, b) {
+		(true, _) => 1,
+		(false, true) => 2,
+	}
+}
+`
+	assertDiagnostics(t, input, []string{
+		"5:8: Match is not exhaustive, missing Color.Green, Color.Blue",
+		"11:8: Match is not exhaustive, missing Shape.Rect(_), Shape.Empty",
+		"17:8: Match is not exhaustive",
+		"24:8: Match is not exhaustive, missing (false, false)",
+	})
+}
+
+func TestCheckMatch_NestedUnion(t *testing.T) {
+	input := `
+enum Size { Small, Large }
+union Shape { Circle(Size), Rect({ w: Size, h: Size }) }
+
+fn area(s: Shape) int {
+	return match s {
+		Shape.Circle(Size.Small) => 1,
+		Shape.Rect(Size.Large, _) => 2,
+		Shape.Rect(_, Size.Small) => 3,
+	}
+}
+`
+	assertDiagnostics(t, input, []string{
+		"5:8: Match is not exhaustive, missing Shape.Circle(Size.Large), Shape.Rect(Size.Small, Size.Large)",
+	})
+}
+
+func TestCheckMatch_Unreachable(t *testing.T) {
+	input := `
+enum Color { Red, Green }
+
+fn name(c: Color) string {
+	return match c {
+		Color.Red => "red",
+		_ => "other",
+		Color.Green => "green",
+		Color.Red => "red",
+	}
+}
+
+fn count(n: int) string {
+	return match n {
+		1 => "one",
+		1 => "uno",
+		n => "many",
+	}
+}
+`
+	assertDiagnostics(t, input, []string{
+		"7:2: Unreachable match arm",
+		"8:2: Unreachable match arm",
+		"15:2: Unreachable match arm",
+	})
+}
+
+func TestCheckMatch_InvalidPatterns(t *testing.T) {
+	input := `
+enum Color { Red, Green }
+union Option { Some(int), None }
+union Result { Ok(int), Err(string) }
+union Either { Ok(int), Other(int) }
+
+fn name(c: Color, o: Option) string {
+	let s: string = match c {
+		Color.Blue => "blue",
+		Color.Red(1) => "red",
+	}
+	match o {
+		Option.Maybe => "maybe",
+		Option.None(1) => "none",
+		Shape.Circle => "circle",
+		Maybe(x) => "maybe",
+		Ok(x) => "ok",
+	}
+}
+`
+	assertDiagnostics(t, input, []string{
+		"8:2: Enum Color has no member Blue",
+		"9:2: Enum member Color.Red has no payload",
+		"12:2: Union Option has no variant Maybe",
+		"13:2: Variant Option.None has no payload",
+		"14:2: Unknown enum or union Shape",
+		"15:2: Unknown variant Maybe",
+		"16:2: Ambiguous variant Ok of Either, Result",
+	})
+}
+
+func TestCheckAssignment(t *testing.T) {
+	input := `
+fn f(n: int, o: Option) {
+	let mut total = 0
+	let count = 0
+	let p = point
+	total += n
+	count = 1
+	p.x = 1
+	n = 2
+	for i = 0; i < n; i += 1 {
+		let count = i
+		count += 1
+	}
+	for x in xs {
+		x = 1
+		total = x
+	}
+	match o {
+		Option.Some(v) => { v = 1 }
+		_ => { unknown = 1 }
+	}
+	xs[0] = 1
+}
+
+union Option { Some(int), None }
+`
+	assertDiagnostics(t, input, []string{
+		"6:1: Cannot assign to count, it is not mutable",
+		"7:1: Cannot assign to p, it is not mutable",
+		"8:1: Cannot assign to parameter n",
+		"11:2: Cannot assign to count, it is not mutable",
+		"14:2: Cannot assign to loop variable x",
+		"18:22: Cannot assign to match binding v",
+	})
+}
+
+func TestCheckLet_Types(t *testing.T) {
+	input := `
+enum Color { Red, Green }
+
+fn f(name: string) {
+	let a: int = 1
+	let b: float = 2
+	let c: string = 3
+	let d: bool = 1 < 2
+	let e: int = name
+	let mut f: Color = Color.Red
+	f = Color.Green
+	f = 1
+	let mut g: string
+	g = "ok"
+	g = true
+	let h = 1.5
+	let i: int = h
+	for n in 0..10 {
+		let s: string = n
+	}
+	let j: int
+}
+`
+	assertDiagnostics(t, input, []string{
+		"6:5: Cannot use int value as string",
+		"8:5: Cannot use string value as int",
+		"11:3: Cannot use int value as Color",
+		"14:3: Cannot use bool value as string",
+		"16:5: Cannot use float value as int",
+		"18:6: Cannot use int value as string",
+		"20:5: Variable j has no value and is not mutable",
+	})
+}
+
+func TestCheckCall_LabeledArguments(t *testing.T) {
+	input := `
+fn link(href: string, text: string) {}
+
+fn main(href: string) {
+	link(href, text: "Home")
+	link(text: "Home", ~href)
+	link(href:, txt: "Home")
+	link(href, href: "/")
+	link(text: "Home", href)
+	link(href, "Home", "title")
+	link(text: "Home", text: "Away")
+	fmt.Println(a: 1)
+	let f = fn(a: int) {}
+	f(a: 1)
+	self.link(href: "/")
+}
+`
+	assertDiagnostics(t, input, []string{
+		"6:13: link has no parameter named txt",
+		"6:5: Missing argument text in call to link",
+		"7:12: Duplicate argument href in call to link",
+		"7:5: Missing argument text in call to link",
+		"8:5: Positional arguments must come before labeled arguments in call to link",
+		"9:5: Too many arguments in call to link",
+		"10:20: Duplicate argument text in call to link",
+		"10:5: Missing argument href in call to link",
+		"11:13: Cannot label argument a of an undeclared function",
+		"13:3: Cannot label argument a of an undeclared function",
+		"14:11: Cannot label argument href of an undeclared function",
+	})
+}
+
+func TestCheckCall_OptionalParams(t *testing.T) {
+	input := `
+fn link(href: string, id?: string, text: string = "Home", size: int = "big") {}
+
+fn main() {
+	link("/")
+	link("/", size: 2)
+	link(id: "a")
+}
+`
+	assertDiagnostics(t, input, []string{
+		"1:58: Cannot use string value as int",
+		"6:5: Missing argument href in call to link",
+	})
+}
+
+func TestCheckCall_VariadicParams(t *testing.T) {
+	input := `
+fn join(sep: string, items: ...string) string {}
+
+fn dup(a: int, a: int) {}
+
+fn main() {
+	join(", ")
+	join(", ", "a", "b", "c")
+	join(items: "a", sep: ", ")
+	join()
+}
+`
+	assertDiagnostics(t, input, []string{
+		"3:15: Duplicate parameter a",
+		"8:6: Variadic argument items cannot be labeled in call to join",
+		"9:5: Missing argument sep in call to join",
+	})
+}
+
+func TestCheckCall_GenericFuncs(t *testing.T) {
+	input := `
+fn id<T>(a: T) T {
+	let b: T = a
+	let f: fn(T) T = fn(x: T) T { x }
+	b
+}
+
+fn repeat<T>(item: T, times: int) {}
+
+struct Box<T> {
+	item: T
+
+	fn put(item: T) {}
+}
+
+fn main() {
+	id(1)
+	id("a")
+	repeat(item: 1.5, times: 2)
+	repeat("a", "b")
+}
+`
+	assertDiagnostics(t, input, []string{
+		"19:7: Cannot use string value as int",
+	})
+}
+
+func TestCheckFuncLiteral(t *testing.T) {
+	input := `
+fn main(count: int) {
+	let limit = 10
+	let inc = fn(n: int, by: int = 1) int {
+		limit = n
+		n + count
+	}
+	let f: fn(int) int = inc
+	let g: fn(int, int) int = inc
+	let h: fn(string) = fn(s: string) {}
+	let i: fn(int) = fn(n: string) {}
+}
+`
+	assertDiagnostics(t, input, []string{
+		"3:22: Parameter by of an anonymous function cannot be optional or have a default value",
+		"4:2: Cannot assign to limit, it is not mutable",
+		"7:5: Cannot use fn(int, int) int value as fn(int) int",
+		"10:5: Cannot use fn(string) value as fn(int)",
+	})
+}
+
+func TestCheckMethods(t *testing.T) {
+	input := `
+enum Color { Red, Green }
+
+struct Rect {
+	w: int
+	fn area() int { self.w * 2 }
+	fn w() int { 1 }
+	fn grow(by: int = 1) {
+		self.w = by
+	}
+}
+
+fn area() for Rect int { 0 }
+fn name() for Color string { "" }
+fn area(self: Rect) int { self.w }
+`
+	assertDiagnostics(t, input, []string{
+		"6:4: Rect has both a field and a method named w",
+		"12:3: Duplicate method Rect.area",
+		"13:14: Cannot declare method name for Color, it is not a struct",
+		"7:9: Parameter by of a method cannot be optional or have a default value",
+		"8:2: Cannot assign to parameter self",
+	})
+}
+
+func TestCheckInterfaces(t *testing.T) {
+	input := `
+interface Printer {
+	write(line: string)
+	flush() bool
+	write(n: int)
+}
+
+interface Writer { write(line: string) }
+
+struct Console {
+	fn write(line: string) {}
+	fn flush() bool { true }
+}
+
+struct Broken {
+	fn write(line: int) {}
+}
+
+struct Empty {}
+
+fn log(p: Printer) {}
+
+fn main(c: Console, b: Broken, e: Empty, w: Writer) {
+	log(c)
+	log(b)
+	log(p: e)
+	let p: Printer = w
+	let mut q: Writer = c
+	q = 1
+}
+`
+	assertDiagnostics(t, input, []string{
+		"4:1: Duplicate method Printer.write",
+		"24:4: Broken does not implement Printer, write has type fn(int) but Printer requires fn(string)",
+		"24:4: Broken does not implement Printer, missing method flush",
+		"25:5: Empty does not implement Printer, missing methods write, flush",
+		"26:5: Writer does not implement Printer, missing method flush",
+		"28:3: int does not implement Writer, missing method write",
+	})
+}
+
+func TestCheck_SyntaxErrors(t *testing.T) {
+	input := `
+fn {}
+
+fn f() {
+	let = f()
+	if x { let = f() }
+}
+`
+	assertDiagnostics(t, input, []string{
+		"1:3: Expected name",
+		"4:5: Expected name",
+		"5:12: Expected name",
+	})
+}
+
+func TestCheckMatch_Values(t *testing.T) {
+	input := `
+enum Color { Red, Green }
+
+fn f(c: int) int {
+	let a = match c { 1 => 2, _ => 3 }
+	let b = match c { 1 => Color.Red, n => match n { _ => Color.Green } }
+	let d = match c { n => n }
+	let e: int = match c { n => n }
+	let s: string = a
+	let t: int = b
+	match c { n => n }
+}
+`
+	assertDiagnostics(t, input, []string{
+		"6:9: Cannot infer the type of match",
+		"8:5: Cannot use int value as string",
+		"9:5: Cannot use Color value as int",
+	})
+}
+
+func TestCheckUnion_Generic(t *testing.T) {
+	input := `
+union Option<T> { Some(T), None }
+union Shape { Circle(int), Empty }
+`
+	assertDiagnostics(t, input, []string{
+		"1:6: Union Option cannot have type parameters",
+	})
+}
+
+func TestCheckMatch_UnqualifiedVariants(t *testing.T) {
+	input := `
+union Result { Ok(int), Err(string) }
+union Option { Some(Result), None }
+
+fn f(o: Option) int {
+	match o {
+		Some(Ok(0)) => -1,
+		Some(Ok(n)) => n,
+		Some(Err(_)) => 0,
+	}
+}
+`
+	assertDiagnostics(t, input, []string{
+		"5:1: Match is not exhaustive, missing Option.None",
+	})
+}
+
+func TestCheckRange(t *testing.T) {
+	input := `
+fn main(n: int) {
+	for i in 0..n {
+		let r = 0..=i
+	}
+	let items = <ul>{for i in 1..n { <li>{i}</li> }}</ul>
+	print(0..n)
+}
+`
+	assertDiagnostics(t, input, []string{
+		"3:11: Cannot use range as a value",
+		"6:8: Cannot use range as a value",
+	})
+}
+
+func TestCheckElement_Keys(t *testing.T) {
+	input := `
+fn List(users: Users) Node {
+	<ul key="list">
+		{for user in users { <li key={user.id}>{user.name}</li> }}
+		{for user in users { <li key={user.id} /> <hr /> }}
+		{for user in users { <>{<b key={user.id} />}</> }}
+	</ul>
+}
+`
+	assertDiagnostics(t, input, []string{
+		"2:1: Key must be on the only element of a for loop body",
+		"4:23: Key must be on the only element of a for loop body",
+		"5:26: Key must be on the only element of a for loop body",
+	})
+}
+
+func TestCheckTypeConversion(t *testing.T) {
+	input := `
+fn f(n: int, c: char, s: string, x: float) {
+	let a = int(c) + int(x)
+	let b = float(n)
+	let d = string(c) + string(s)
+	let e = char(n)
+	let g = string(n)
+	let h = string(1.5)
+	let i = char("ab")
+	let j = int(s)
+	let k = string(unknown)
+}
+`
+	assertDiagnostics(t, input, []string{
+		"6:9: Cannot convert int to string",
+		"7:9: Cannot convert float to string",
+		"8:9: Cannot convert string to char",
+		"9:9: Cannot convert string to int",
+	})
+}
+
+func TestCheckExpressionStatements(t *testing.T) {
+	input := `
+union Option { Some(int), None }
+
+fn f(x: int, o: Option) int {
+	x + 1
+	print(x)
+	Option.Some(x)
+	Some(x)
+	match o {
+		Some(n) => n,
+		None => print(x),
+	}
+	let g = fn() int { x; x * 2 }
+	x * 2
+}
+
+fn g(x: int, o: Option) {
+	x
+}
+
+fn h(o: Option) int {
+	match o {
+		Some(n) => match n {
+			0 => 0,
+			_ => n,
+		},
+		None => { print(1); 1 },
+	}
+}
+`
+	assertDiagnostics(t, input, []string{
+		"4:1: Value of expression is not used",
+		"6:1: Value of expression is not used",
+		"7:1: Value of expression is not used",
+		"9:2: Value of match arm is not used",
+		"12:20: Value of expression is not used",
+		"17:1: Value of expression is not used",
+	})
+}
+
+func TestCheckMatch_UnitVariants(t *testing.T) {
+	input := `
+enum Color { Red, Green }
+union Shape { Circle(float), Rect, Empty }
+union Option { Some(Shape), None }
+union Status { Idle, Done(int) }
+
+fn f(o: Option) int {
+	match o {
+		Some(Circle(_)) => 1,
+		None => 0,
+	}
+}
+
+fn g(c: Color) int {
+	match c {
+		Red => 1,
+		other => 0,
+		Green => 2,
+	}
+}
+
+fn h(s: Status) int {
+	match s {
+		Idle => 0,
+		Done => 1,
+	}
+}
+`
+	assertDiagnostics(t, input, []string{
+		"7:1: Match is not exhaustive, missing Option.Some(Shape.Rect), Option.Some(Shape.Empty)",
+		"17:2: Unreachable match arm",
+	})
+}
+
+func TestCheckMatch_AmbiguousUnitVariants(t *testing.T) {
+	input := `
+union Option { Some(int), None }
+union Status { None, Done }
+
+fn f(o: Option) int {
+	match o {
+		Some(_) => 1,
+		None => 0,
+	}
+}
+`
+	assertDiagnostics(t, input, []string{
+		"7:2: Ambiguous variant None of Option, Status",
+	})
+}
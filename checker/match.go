@@ -0,0 +1,475 @@
+package checker
+
+import (
+	"fmt"
+	"gloss/ast"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Exhaustiveness is checked by finding the values which no arm matches, using
+// the usefulness algorithm of "Warnings for pattern matching" (Maranget,
+// 2007). Patterns are first reduced to constructors applied to sub-patterns,
+// e.g. Shape.Circle(_) or (1, true), and wildcards.
+
+// ctor is a constructor of a value which patterns can test for.
+type ctor struct {
+	key   string // Identifies the constructor within its type
+	name  string // Name of the constructor as written in gloss
+	arity int
+	tuple bool
+
+	// All constructors of the type, or nil when there are too many to list,
+	// e.g. the values of int
+	siblings func() []*ctor
+}
+
+// pat is a pattern reduced to a constructor and its arguments. Wildcards and
+// bindings, which match any value, have no constructor.
+type pat struct {
+	ctor *ctor
+	args []*pat
+}
+
+var wildcard = &pat{}
+
+// checkMatch reports the values of the subject which no arm of a match
+// matches, and the arms which cannot match as earlier arms match their
+// values. When value is set each arm has a value, and otherwise the match is
+// a statement whose arms must be calls.
+func (c *Checker) checkMatch(node *ast.Match, value bool) {
+	c.checkExpression(node.Subject)
+	for _, arm := range node.Arms {
+		arm.Pattern = c.resolveBindings(arm.Pattern)
+		c.openScope()
+		c.declareBindings(arm.Pattern)
+		if arm.Body != nil {
+			c.checkBlock(arm.Body, value)
+		} else if m, ok := arm.Value.(*ast.Match); ok {
+			// Nested matches are compiled as part of the enclosing match
+			c.checkMatch(m, value)
+		} else {
+			if !value && arm.Value != nil && !c.isCall(arm.Value) {
+				c.Diagnostics.Error(arm.Token, "Value of match arm is not used")
+			}
+			c.checkExpression(arm.Value)
+		}
+		c.closeScope()
+	}
+
+	var rows [][]*pat
+	valid := true
+	for _, arm := range node.Arms {
+		if arm.Pattern == nil {
+			// Reported by the parser
+			valid = false
+			continue
+		}
+		p, ok := c.reduce(arm.Pattern)
+		if !ok {
+			valid = false
+			continue
+		}
+
+		if len(missing(rows, []*pat{p})) == 0 {
+			c.Diagnostics.Warn(arm.Token, "Unreachable match arm")
+		}
+		rows = append(rows, []*pat{p})
+	}
+
+	// Arms with invalid patterns may have covered the missing values
+	if !valid {
+		return
+	}
+
+	witnesses := missing(rows, []*pat{wildcard})
+	if len(witnesses) == 0 {
+		return
+	}
+
+	if len(witnesses) == 1 && witnesses[0][0].ctor == nil {
+		c.Diagnostics.Error(node.Token, "Match is not exhaustive")
+		return
+	}
+
+	names := make([]string, len(witnesses))
+	for i, w := range witnesses {
+		names[i] = w[0].String()
+	}
+	c.Diagnostics.Error(node.Token, fmt.Sprintf("Match is not exhaustive, missing %s", strings.Join(names, ", ")))
+}
+
+// resolveBindings resolves the bare names in a pattern which name an enum
+// member or union variant, e.g. None, to variant patterns, so that they do
+// not bind the value.
+func (c *Checker) resolveBindings(pattern ast.Pattern) ast.Pattern {
+	switch p := pattern.(type) {
+	case *ast.BindingPattern:
+		var types []string
+		for _, e := range c.enums {
+			if slices.ContainsFunc(e.Members, func(m *ast.EnumMember) bool { return m.Name == p.Name }) {
+				types = append(types, e.Name)
+			}
+		}
+		for _, u := range c.unions {
+			if slices.ContainsFunc(u.Fields, func(f *ast.UnionField) bool { return f.Name == p.Name }) {
+				types = append(types, u.Name)
+			}
+		}
+
+		switch len(types) {
+		case 0:
+		case 1:
+			return &ast.VariantPattern{Token: p.Token, Type: types[0], Name: p.Name}
+		default:
+			slices.Sort(types)
+			c.Diagnostics.Error(p.Token, fmt.Sprintf("Ambiguous variant %s of %s", p.Name, strings.Join(types, ", ")))
+			return nil
+		}
+	case *ast.TuplePattern:
+		for i, item := range p.Items {
+			p.Items[i] = c.resolveBindings(item)
+		}
+	case *ast.VariantPattern:
+		p.Payload = c.resolveBindings(p.Payload)
+	}
+	return pattern
+}
+
+// declareBindings declares the variables bound by a pattern.
+func (c *Checker) declareBindings(pattern ast.Pattern) {
+	switch p := pattern.(type) {
+	case *ast.BindingPattern:
+		c.declare(p.Name, matchBinding, "")
+	case *ast.TuplePattern:
+		for _, item := range p.Items {
+			c.declareBindings(item)
+		}
+	case *ast.VariantPattern:
+		c.declareBindings(p.Payload)
+	}
+}
+
+// missing returns the values matched by the pattern vector q which no row
+// of the matrix matches. The result is empty when q is not useful, i.e. the
+// rows already match every value matched by q.
+func missing(rows [][]*pat, q []*pat) [][]*pat {
+	if len(q) == 0 {
+		if len(rows) == 0 {
+			return [][]*pat{{}}
+		}
+		return nil
+	}
+
+	if head := q[0]; head.ctor != nil {
+		rest := append(append([]*pat{}, head.args...), q[1:]...)
+		return rebuild(head.ctor, missing(specialize(rows, head.ctor), rest))
+	}
+
+	// Constructors tested for by the first column
+	var used []*ctor
+	seen := map[string]bool{}
+	for _, row := range rows {
+		if k := row[0].ctor; k != nil && !seen[k.key] {
+			seen[k.key] = true
+			used = append(used, k)
+		}
+	}
+
+	var all []*ctor
+	if len(used) > 0 && used[0].siblings != nil {
+		all = used[0].siblings()
+	}
+
+	var unused []*ctor
+	for _, k := range all {
+		if !seen[k.key] {
+			unused = append(unused, k)
+		}
+	}
+
+	// Every constructor of the type is tested for, so the values missed are
+	// those missed within each constructor
+	if len(all) > 0 && len(unused) == 0 {
+		var result [][]*pat
+		for _, k := range all {
+			args := make([]*pat, k.arity, k.arity+len(q)-1)
+			for i := range args {
+				args[i] = wildcard
+			}
+			result = append(result, rebuild(k, missing(specialize(rows, k), append(args, q[1:]...)))...)
+		}
+		return result
+	}
+
+	// Otherwise the values missed are those of the unused constructors, or
+	// any value when they cannot be listed, for which the rows starting with
+	// a wildcard do not match the rest of q
+	var defaults [][]*pat
+	for _, row := range rows {
+		if row[0].ctor == nil {
+			defaults = append(defaults, row[1:])
+		}
+	}
+
+	rest := missing(defaults, q[1:])
+	if len(rest) == 0 {
+		return nil
+	}
+
+	heads := []*pat{wildcard}
+	if len(unused) > 0 {
+		heads = heads[:0]
+		for _, k := range unused {
+			args := make([]*pat, k.arity)
+			for i := range args {
+				args[i] = wildcard
+			}
+			heads = append(heads, &pat{ctor: k, args: args})
+		}
+	}
+
+	var result [][]*pat
+	for _, head := range heads {
+		for _, r := range rest {
+			result = append(result, append([]*pat{head}, r...))
+		}
+	}
+	return result
+}
+
+// specialize returns the rows of the matrix which match values built with
+// the constructor k, replacing their first pattern with its arguments.
+func specialize(rows [][]*pat, k *ctor) [][]*pat {
+	var result [][]*pat
+	for _, row := range rows {
+		head := row[0]
+		switch {
+		case head.ctor == nil:
+			args := make([]*pat, k.arity, k.arity+len(row)-1)
+			for i := range args {
+				args[i] = wildcard
+			}
+			result = append(result, append(args, row[1:]...))
+		case head.ctor.key == k.key:
+			result = append(result, append(append([]*pat{}, head.args...), row[1:]...))
+		}
+	}
+	return result
+}
+
+// rebuild applies the constructor k to the first arguments of each value.
+func rebuild(k *ctor, values [][]*pat) [][]*pat {
+	result := make([][]*pat, len(values))
+	for i, v := range values {
+		result[i] = append([]*pat{{ctor: k, args: v[:k.arity]}}, v[k.arity:]...)
+	}
+	return result
+}
+
+// reduce reduces a pattern to its constructor and arguments, reporting
+// patterns naming unknown enum members or variants.
+func (c *Checker) reduce(pattern ast.Pattern) (*pat, bool) {
+	switch p := pattern.(type) {
+	case *ast.WildcardPattern, *ast.BindingPattern:
+		return wildcard, true
+
+	case *ast.LiteralPattern:
+		return &pat{ctor: literalCtor(p.Value)}, true
+
+	case *ast.TuplePattern:
+		return c.reduceTuple(p.Items)
+
+	case *ast.VariantPattern:
+		return c.reduceVariant(p)
+	}
+	return nil, false
+}
+
+func (c *Checker) reduceTuple(items []ast.Pattern) (*pat, bool) {
+	k := tupleCtor(len(items))
+	result := &pat{ctor: k}
+	for _, item := range items {
+		arg, ok := c.reduce(item)
+		if !ok {
+			return nil, false
+		}
+		result.args = append(result.args, arg)
+	}
+	return result, true
+}
+
+func (c *Checker) reduceVariant(p *ast.VariantPattern) (*pat, bool) {
+	if e, ok := c.enums[p.Type]; ok {
+		for _, m := range e.Members {
+			if m.Name != p.Name {
+				continue
+			}
+			if p.Payload != nil {
+				c.Diagnostics.Error(p.Token, fmt.Sprintf("Enum member %s.%s has no payload", e.Name, m.Name))
+				return nil, false
+			}
+			return &pat{ctor: memberCtor(e, m)}, true
+		}
+		c.Diagnostics.Error(p.Token, fmt.Sprintf("Enum %s has no member %s", e.Name, p.Name))
+		return nil, false
+	}
+
+	var u *ast.Union
+	if p.Type == "" {
+		if u = c.unionOf(p); u == nil {
+			return nil, false
+		}
+	} else if u = c.unions[p.Type]; u == nil {
+		c.Diagnostics.Error(p.Token, fmt.Sprintf("Unknown enum or union %s", p.Type))
+		return nil, false
+	}
+
+	for _, f := range u.Fields {
+		if f.Name != p.Name {
+			continue
+		}
+
+		k := variantCtor(u, f)
+		switch {
+		case f.Type == nil && p.Payload != nil:
+			c.Diagnostics.Error(p.Token, fmt.Sprintf("Variant %s.%s has no payload", u.Name, f.Name))
+			return nil, false
+		case f.Type == nil, p.Payload == nil:
+			// Variants with a payload may be matched by name alone
+			if k.arity == 0 {
+				return &pat{ctor: k}, true
+			}
+			return &pat{ctor: k, args: []*pat{wildcard}}, true
+		}
+
+		payload, ok := c.reduce(p.Payload)
+		if !ok {
+			return nil, false
+		}
+		return &pat{ctor: k, args: []*pat{payload}}, true
+	}
+	c.Diagnostics.Error(p.Token, fmt.Sprintf("Union %s has no variant %s", u.Name, p.Name))
+	return nil, false
+}
+
+// unionOf resolves the union of a variant whose union is omitted, e.g.
+// Some(x), reporting variants which no union or more than one union has.
+func (c *Checker) unionOf(p *ast.VariantPattern) *ast.Union {
+	var found []string
+	for _, u := range c.unions {
+		for _, f := range u.Fields {
+			if f.Name == p.Name {
+				found = append(found, u.Name)
+			}
+		}
+	}
+
+	switch len(found) {
+	case 0:
+		c.Diagnostics.Error(p.Token, fmt.Sprintf("Unknown variant %s", p.Name))
+		return nil
+	case 1:
+		return c.unions[found[0]]
+	}
+	slices.Sort(found)
+	c.Diagnostics.Error(p.Token, fmt.Sprintf("Ambiguous variant %s of %s", p.Name, strings.Join(found, ", ")))
+	return nil
+}
+
+func memberCtor(e *ast.Enum, m *ast.EnumMember) *ctor {
+	return &ctor{
+		key:  m.Name,
+		name: e.Name + "." + m.Name,
+		siblings: func() []*ctor {
+			all := make([]*ctor, len(e.Members))
+			for i, m := range e.Members {
+				all[i] = memberCtor(e, m)
+			}
+			return all
+		},
+	}
+}
+
+func variantCtor(u *ast.Union, f *ast.UnionField) *ctor {
+	k := &ctor{
+		key:  f.Name,
+		name: u.Name + "." + f.Name,
+		siblings: func() []*ctor {
+			all := make([]*ctor, len(u.Fields))
+			for i, f := range u.Fields {
+				all[i] = variantCtor(u, f)
+			}
+			return all
+		},
+	}
+	if f.Type != nil {
+		k.arity = 1
+	}
+	return k
+}
+
+// tupleCtor is the only constructor of tuples with n items.
+func tupleCtor(n int) *ctor {
+	k := &ctor{key: "(" + strconv.Itoa(n) + ")", arity: n, tuple: true}
+	k.siblings = func() []*ctor { return []*ctor{k} }
+	return k
+}
+
+// literalCtor returns the constructor of a literal value. Only booleans have
+// few enough values to be listed.
+func literalCtor(value ast.Expression) *ctor {
+	switch v := value.(type) {
+	case *ast.Boolean:
+		k := &ctor{key: strconv.FormatBool(v.Value), name: strconv.FormatBool(v.Value)}
+		k.siblings = func() []*ctor {
+			return []*ctor{
+				{key: "true", name: "true", siblings: k.siblings},
+				{key: "false", name: "false", siblings: k.siblings},
+			}
+		}
+		return k
+	case *ast.IntegerLiteral:
+		return &ctor{key: "int:" + strconv.FormatInt(v.Value, 10)}
+	case *ast.FloatLiteral:
+		return &ctor{key: "float:" + strconv.FormatFloat(v.Value, 'g', -1, 64)}
+	case *ast.StringLiteral:
+		return &ctor{key: "string:" + v.Value}
+	case *ast.CharLiteral:
+		return &ctor{key: "char:" + string(v.Value)}
+	case *ast.UnaryExpression:
+		// Negative numbers, e.g. -1
+		k := literalCtor(v.Right)
+		k.key = v.Operator + k.key
+		return k
+	}
+	return &ctor{key: fmt.Sprintf("%p", value)}
+}
+
+// String formats the pattern as written in gloss, e.g. Shape.Rect(_, _).
+func (p *pat) String() string {
+	switch {
+	case p.ctor == nil:
+		return "_"
+	case p.ctor.tuple:
+		return "(" + joinPats(p.args) + ")"
+	case len(p.args) == 0:
+		return p.ctor.name
+	}
+
+	// Struct payloads are matched by a tuple of their fields, which is written
+	// without its own parentheses
+	if arg := p.args[0]; arg.ctor != nil && arg.ctor.tuple && len(p.args) == 1 {
+		return p.ctor.name + arg.String()
+	}
+	return p.ctor.name + "(" + joinPats(p.args) + ")"
+}
+
+func joinPats(pats []*pat) string {
+	s := make([]string, len(pats))
+	for i, p := range pats {
+		s[i] = p.String()
+	}
+	return strings.Join(s, ", ")
+}
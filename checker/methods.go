@@ -0,0 +1,141 @@
+package checker
+
+import (
+	"fmt"
+	"gloss/ast"
+	"gloss/token"
+	"strings"
+)
+
+// selfVar is the receiver of methods.
+const selfVar = "self"
+
+// declareMethods records the methods of the structs of a file, which are
+// declared in the body of the struct or for the struct, e.g.
+// fn area() for Rect int.
+func (c *Checker) declareMethods(file *ast.SourceFile) {
+	for _, node := range file.Declarations {
+		switch n := node.(type) {
+		case *ast.Struct:
+			for _, method := range n.Methods {
+				c.declareMethod(n, method)
+			}
+		case *ast.Func:
+			if n.Receiver == nil {
+				continue
+			}
+			s, ok := c.structs[n.Receiver.Name]
+			if !ok {
+				c.Diagnostics.Error(n.Receiver.Token, fmt.Sprintf("Cannot declare method %s for %s, it is not a struct", n.Name, n.Receiver.Name))
+				continue
+			}
+			c.declareMethod(s, n)
+		}
+	}
+}
+
+func (c *Checker) declareMethod(s *ast.Struct, method *ast.Func) {
+	methods := c.methods[s.Name]
+	if methods == nil {
+		methods = map[string]*ast.Func{}
+		c.methods[s.Name] = methods
+	}
+
+	switch {
+	case methods[method.Name] != nil:
+		c.Diagnostics.Error(method.Token, fmt.Sprintf("Duplicate method %s.%s", s.Name, method.Name))
+	case field(s, method.Name) != nil:
+		c.Diagnostics.Error(method.Token, fmt.Sprintf("%s has both a field and a method named %s", s.Name, method.Name))
+	default:
+		methods[method.Name] = method
+	}
+}
+
+// field returns the field of a struct with the given name.
+func field(s *ast.Struct, name string) *ast.StructField {
+	for _, f := range s.Fields {
+		if f.Name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+// checkInterface reports methods of an interface which share a name, and
+// parameters which could not be left out of calls.
+func (c *Checker) checkInterface(node *ast.Interface) {
+	seen := map[string]bool{}
+	for _, m := range node.Methods {
+		if seen[m.Name] {
+			c.Diagnostics.Error(m.Token, fmt.Sprintf("Duplicate method %s.%s", node.Name, m.Name))
+		}
+		seen[m.Name] = true
+
+		c.checkRequiredParams(m.Params, "an interface method")
+		c.openScope()
+		c.checkParams(m.Params)
+		c.closeScope()
+	}
+}
+
+// checkImplements reports values of the type typ which cannot be used as an
+// interface, listing the methods of the interface which typ does not
+// declare. Types whose methods are not known are not checked.
+func (c *Checker) checkImplements(tok token.Token, typ string, iface *ast.Interface) {
+	methods, ok := c.methodsOf(typ)
+	if !ok {
+		return
+	}
+
+	var missing []string
+	seen := map[string]bool{}
+	for _, want := range iface.Methods {
+		// Duplicate methods are reported by checkInterface
+		if seen[want.Name] {
+			continue
+		}
+		seen[want.Name] = true
+
+		got, ok := methods[want.Name]
+		if !ok {
+			missing = append(missing, want.Name)
+			continue
+		}
+
+		gotSig, wantSig := signature(got.Params, got.ReturnType, typeParams(got)), signature(want.Params, want.ReturnType, want.TypeParams)
+		if gotSig != "" && wantSig != "" && gotSig != wantSig {
+			c.Diagnostics.Error(tok, fmt.Sprintf("%s does not implement %s, %s has type %s but %s requires %s", typ, iface.Name, want.Name, gotSig, iface.Name, wantSig))
+		}
+	}
+
+	switch len(missing) {
+	case 0:
+	case 1:
+		c.Diagnostics.Error(tok, fmt.Sprintf("%s does not implement %s, missing method %s", typ, iface.Name, missing[0]))
+	default:
+		c.Diagnostics.Error(tok, fmt.Sprintf("%s does not implement %s, missing methods %s", typ, iface.Name, strings.Join(missing, ", ")))
+	}
+}
+
+// methodsOf returns the methods of a type by name, and whether they are
+// known. Builtin types, enums and unions have no methods.
+func (c *Checker) methodsOf(typ string) (map[string]*ast.Func, bool) {
+	if _, ok := c.structs[typ]; ok {
+		return c.methods[typ], true
+	}
+	if iface, ok := c.interfaces[typ]; ok {
+		methods := map[string]*ast.Func{}
+		for _, m := range iface.Methods {
+			methods[m.Name] = m
+		}
+		return methods, true
+	}
+
+	switch typ {
+	case "int", "float", "string", "char", "bool":
+		return nil, true
+	}
+	_, enum := c.enums[typ]
+	_, union := c.unions[typ]
+	return nil, enum || union
+}
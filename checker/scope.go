@@ -0,0 +1,52 @@
+package checker
+
+// varKind describes how a variable was declared, which decides whether it
+// may be assigned.
+type varKind string
+
+const (
+	variable        varKind = "variable"
+	mutableVariable varKind = "mutable variable"
+	parameter       varKind = "parameter"
+	loopVariable    varKind = "loop variable"
+	matchBinding    varKind = "match binding"
+)
+
+// symbol is a declared variable. The name of its type is empty when it is
+// not known, e.g. for the items of a slice.
+type symbol struct {
+	kind varKind
+	typ  string
+}
+
+// scope holds the variables declared in a block, falling back to the
+// enclosing scopes for names it does not declare.
+type scope struct {
+	parent *scope
+	vars   map[string]*symbol
+}
+
+func (c *Checker) openScope() {
+	c.scope = &scope{parent: c.scope, vars: map[string]*symbol{}}
+}
+
+func (c *Checker) closeScope() {
+	c.scope = c.scope.parent
+}
+
+// declare declares a variable in the current scope, shadowing any variable
+// of the same name in the enclosing scopes.
+func (c *Checker) declare(name string, kind varKind, typ string) {
+	c.scope.vars[name] = &symbol{kind: kind, typ: typ}
+}
+
+// lookup returns the variable with the given name, or nil for names which
+// are not declared, e.g. those of functions.
+func (c *Checker) lookup(name string) *symbol {
+	for s := c.scope; s != nil; s = s.parent {
+		if v, ok := s.vars[name]; ok {
+			return v
+		}
+	}
+	return nil
+}
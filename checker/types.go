@@ -0,0 +1,222 @@
+package checker
+
+import (
+	"fmt"
+	"gloss/ast"
+	"gloss/token"
+	"slices"
+	"strings"
+)
+
+// Types are only known for builtin values, enums, unions and functions of
+// them, e.g. "int", "Shape" or "fn(int) Shape", and are otherwise empty.
+// Values of unknown types are not checked.
+
+// typeName returns the name of a type, or "" for types which are not
+// checked, e.g. struct bodies and the type parameters params of a generic
+// function, which stand for any type.
+func typeName(t ast.Type, params []*ast.TypeParameter) string {
+	switch t := t.(type) {
+	case *ast.TypeLiteral:
+		return t.Type
+	case *ast.TypeIdentifier:
+		for _, param := range params {
+			if param.Name == t.Name {
+				return ""
+			}
+		}
+		if len(t.Parameters) == 0 {
+			return t.Name
+		}
+	case *ast.FuncType:
+		return funcTypeName(t.Params, t.ReturnType, params)
+	}
+	return ""
+}
+
+// funcTypeName returns the name of the type of a function, e.g.
+// "fn(int) string", or "" when the type of a parameter or the result is not
+// checked.
+func funcTypeName(params []ast.Type, result ast.Type, typeParams []*ast.TypeParameter) string {
+	names := make([]string, len(params))
+	for i, param := range params {
+		if names[i] = typeName(param, typeParams); names[i] == "" {
+			return ""
+		}
+	}
+
+	name := "fn(" + strings.Join(names, ", ") + ")"
+	if result != nil {
+		ret := typeName(result, typeParams)
+		if ret == "" {
+			return ""
+		}
+		name += " " + ret
+	}
+	return name
+}
+
+// signature returns the name of the type of a function with the given
+// parameters and result, or "" when it is not known, e.g. for functions with
+// a variadic parameter.
+func signature(params []*ast.Parameter, result ast.Type, typeParams []*ast.TypeParameter) string {
+	types := make([]ast.Type, len(params))
+	for i, param := range params {
+		if param.Variadic {
+			return ""
+		}
+		types[i] = param.Type
+	}
+	return funcTypeName(types, result, typeParams)
+}
+
+// typeParams returns the type parameters of a function, including those of
+// the type of a method, e.g. T of fn get() for Box<T> T.
+func typeParams(fn *ast.Func) []*ast.TypeParameter {
+	if fn.Receiver == nil {
+		return fn.TypeParams
+	}
+	return append(slices.Clip(fn.TypeParams), fn.Receiver.Parameters...)
+}
+
+// typeOf returns the name of the type of an expression, or "" when it is not
+// known.
+func (c *Checker) typeOf(exp ast.Expression) string {
+	switch e := exp.(type) {
+	case *ast.IntegerLiteral:
+		return "int"
+	case *ast.FloatLiteral:
+		return "float"
+	case *ast.StringLiteral, *ast.TemplateLiteral:
+		return "string"
+	case *ast.CharLiteral:
+		return "char"
+	case *ast.Boolean:
+		return "bool"
+	case *ast.FuncLiteral:
+		return signature(e.Params, e.ReturnType, c.typeParams)
+	case *ast.TypeConversion:
+		return e.Type.Type
+	case *ast.Match:
+		return c.matchType(e)
+	case *ast.ParenExpression:
+		return c.typeOf(e.Expression)
+	case *ast.Identifier:
+		if v := c.lookup(e.Name); v != nil {
+			return v.typ
+		}
+	case *ast.UnaryExpression:
+		if e.Operator == "!" {
+			return "bool"
+		}
+		return c.typeOf(e.Right)
+	case *ast.BinaryExpression:
+		switch e.Operator {
+		case "==", "!=", "<", "<=", ">", ">=", "&&", "||":
+			return "bool"
+		}
+		if left := c.typeOf(e.Left); left == c.typeOf(e.Right) {
+			return left
+		}
+	case *ast.MemberExpression:
+		// Enum members, e.g. Color.Red
+		if obj, ok := e.Object.(*ast.Identifier); ok && c.lookup(obj.Name) == nil {
+			if enum, ok := c.enums[obj.Name]; ok {
+				return enum.Name
+			}
+			if u, ok := c.unions[obj.Name]; ok && len(u.Parameters) == 0 {
+				return u.Name
+			}
+		}
+	case *ast.CallExpression:
+		// Union variants, e.g. Shape.Circle(1.0)
+		if m, ok := e.Function.(*ast.MemberExpression); ok {
+			if obj, ok := m.Object.(*ast.Identifier); ok && c.lookup(obj.Name) == nil {
+				if u, ok := c.unions[obj.Name]; ok && len(u.Parameters) == 0 {
+					return u.Name
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// matchType returns the type of the first arm of a match whose value has a
+// type evident from the value alone, e.g. a literal, conversion, enum member
+// or union variant, or "" if there is none.
+func (c *Checker) matchType(node *ast.Match) string {
+	for _, arm := range node.Arms {
+		value := arm.Value
+		if arm.Body != nil && len(arm.Body.Statements) > 0 {
+			if stmt, ok := arm.Body.Statements[len(arm.Body.Statements)-1].(*ast.ExpressionStatement); ok {
+				value = stmt.Expression
+			}
+		}
+
+		switch v := value.(type) {
+		case *ast.Match:
+			if t := c.matchType(v); t != "" {
+				return t
+			}
+		case *ast.IntegerLiteral, *ast.FloatLiteral, *ast.StringLiteral, *ast.TemplateLiteral, *ast.CharLiteral,
+			*ast.Boolean, *ast.TypeConversion, *ast.MemberExpression, *ast.CallExpression:
+			if t := c.typeOf(v); t != "" {
+				return t
+			}
+		}
+	}
+	return ""
+}
+
+// checkAssignable reports values which cannot be assigned to a variable of
+// the type typ. Integer literals may be assigned to floats, e.g.
+// let x: float = 1, and values to the interfaces their type implements.
+func (c *Checker) checkAssignable(tok token.Token, value ast.Expression, typ string) {
+	got := c.typeOf(value)
+	if got == "" || typ == "" || got == typ {
+		return
+	}
+	if typ == "float" && got == "int" && isConstant(value) {
+		return
+	}
+	if iface, ok := c.interfaces[typ]; ok {
+		c.checkImplements(tok, got, iface)
+		return
+	}
+	c.Diagnostics.Error(tok, fmt.Sprintf("Cannot use %s value as %s", got, typ))
+}
+
+// conversions holds the types of the values which may be converted to each
+// builtin type, e.g. int(c) for a char c. Numbers are not converted to their
+// text, as string(n) is the character with the code n in Go.
+var conversions = map[string][]string{
+	"int":    {"int", "float", "char"},
+	"float":  {"int", "float", "char"},
+	"char":   {"int", "char"},
+	"string": {"string", "char"},
+}
+
+// checkConversion reports conversions of values whose type cannot be
+// converted to the type of the conversion, e.g. char("ab").
+func (c *Checker) checkConversion(conv *ast.TypeConversion) {
+	got, typ := c.typeOf(conv.Value), conv.Type.Type
+	if got == "" || slices.Contains(conversions[typ], got) {
+		return
+	}
+	c.Diagnostics.Error(conv.Token, fmt.Sprintf("Cannot convert %s to %s", got, typ))
+}
+
+// isConstant reports whether an expression only combines literals.
+func isConstant(exp ast.Expression) bool {
+	switch e := exp.(type) {
+	case *ast.IntegerLiteral, *ast.FloatLiteral:
+		return true
+	case *ast.UnaryExpression:
+		return isConstant(e.Right)
+	case *ast.ParenExpression:
+		return isConstant(e.Expression)
+	case *ast.BinaryExpression:
+		return isConstant(e.Left) && isConstant(e.Right)
+	}
+	return false
+}
@@ -0,0 +1,205 @@
+package compiler
+
+import "gloss/ast"
+
+// givenVar holds the mask of the arguments given to a call in the wrappers
+// of functions with default values. It is a keyword in gloss, so it cannot
+// clash with the names of parameters.
+const givenVar = "in"
+
+func (c *Go) compileCallExpression(node *ast.CallExpression) {
+	if u, f := c.variantOf(node.Function); f != nil {
+		c.compileVariant(u, f, node.Arguments)
+		return
+	}
+
+	id, ok := node.Function.(*ast.Identifier)
+	if !ok || c.funcs[id.Name] == nil {
+		c.compileExpression(node.Function)
+		c.compileArguments(node.Arguments)
+		return
+	}
+
+	fn := c.funcs[id.Name]
+	args, rest := arguments(fn, node.Arguments)
+
+	// Parameters left out receive their default value, or the zero value of
+	// their type
+	var given int
+	defaults := false
+	for i, p := range fixedParams(fn) {
+		switch {
+		case args[i] != nil:
+			given |= 1 << i
+		case p.Default != nil:
+			defaults = true
+			fallthrough
+		default:
+			args[i] = &zeroValue{Type: p.Type}
+		}
+	}
+
+	if !defaults {
+		c.compileIdentifier(id)
+		c.compileArguments(append(args, rest...))
+		return
+	}
+
+	// The mask of the arguments given precedes the arguments of a variadic
+	// parameter
+	c.emit("%sDefaults", id.Name)
+	args = append(args, &ast.IntegerLiteral{Value: int64(given)})
+	c.compileArguments(append(args, rest...))
+}
+
+func (c *Go) compileArguments(args []ast.Expression) {
+	c.emit("(")
+	for i, arg := range args {
+		if i > 0 {
+			c.emit(", ")
+		}
+		if z, ok := arg.(*zeroValue); ok {
+			c.compileZeroValue(z.Type)
+			continue
+		}
+		c.compileExpression(arg)
+	}
+	c.emit(")")
+}
+
+// arguments orders the arguments of a call by the parameters of the called
+// function, passing labeled arguments to the parameter of the same name. The
+// arguments of parameters left out of the call are nil, and the remaining
+// positional arguments are returned as the arguments of a variadic parameter.
+// Arguments which cannot be resolved, reported by the checker, are dropped.
+func arguments(fn *ast.Func, args []ast.Expression) (ordered, rest []ast.Expression) {
+	labeled := map[string]ast.Expression{}
+	var positional []ast.Expression
+	for _, arg := range args {
+		if la, ok := arg.(*ast.LabeledArgument); ok {
+			labeled[la.Label] = la.Value
+		} else {
+			positional = append(positional, arg)
+		}
+	}
+
+	params := fixedParams(fn)
+	ordered = make([]ast.Expression, len(params))
+	for i, p := range params {
+		if arg, ok := labeled[p.Name]; ok {
+			ordered[i] = arg
+		} else if len(positional) > 0 {
+			ordered[i] = positional[0]
+			positional = positional[1:]
+		}
+	}
+	if len(params) < len(fn.Params) {
+		rest = positional
+	}
+	return ordered, rest
+}
+
+// fixedParams returns the parameters of a function, excluding a variadic
+// parameter.
+func fixedParams(fn *ast.Func) []*ast.Parameter {
+	if n := len(fn.Params); n > 0 && fn.Params[n-1].Variadic {
+		return fn.Params[:n-1]
+	}
+	return fn.Params
+}
+
+// compileDefaults compiles the wrapper called when arguments with default
+// values are left out of a call to a function. The wrapper takes a mask of
+// the arguments given, and sets the others to their default value before
+// calling the function, so that defaults are evaluated with the parameters
+// in scope, e.g.
+//
+//	func greetDefaults(name string, greeting string, in int) string {
+//	    if in&2 == 0 {
+//	        greeting = "Hello"
+//	    }
+//	    return greet(name, greeting)
+//	}
+func (c *Go) compileDefaults(node *ast.Func) {
+	params := fixedParams(node)
+	c.emit("func %sDefaults(", node.Name)
+	for _, param := range params {
+		c.compileParam(param)
+		c.emit(", ")
+	}
+	c.emit("%s int", givenVar)
+	if len(params) < len(node.Params) {
+		c.emit(", ")
+		c.compileParam(node.Params[len(params)])
+	}
+	c.emit(")")
+	if node.ReturnType != nil {
+		c.emit(" ")
+		c.compileType(node.ReturnType)
+	}
+	c.emit(" {")
+
+	c.indent()
+	for i, param := range node.Params {
+		if param.Default == nil {
+			continue
+		}
+		c.emit("\n")
+		c.emitIndent()
+		c.emit("if %s&%d == 0 {\n", givenVar, 1<<i)
+		c.indent()
+		c.emitIndent()
+		c.emit("%s = ", param.Name)
+		c.compileExpression(param.Default)
+		c.outdent()
+		c.emit("\n")
+		c.emitIndent()
+		c.emit("}")
+	}
+
+	c.emit("\n")
+	c.emitIndent()
+	if node.ReturnType != nil {
+		c.emit("return ")
+	}
+	c.emit("%s(", node.Name)
+	for i, param := range node.Params {
+		if i > 0 {
+			c.emit(", ")
+		}
+		c.emit("%s", param.Name)
+		if param.Variadic {
+			c.emit("...")
+		}
+	}
+	c.emit(")")
+	c.outdent()
+	c.emit("\n}")
+}
+
+// zeroValue stands in for the argument of a parameter left out of a call.
+type zeroValue struct {
+	ast.Expression
+	Type ast.Type
+}
+
+// compileZeroValue compiles the zero value of a type, e.g. "" for strings.
+func (c *Go) compileZeroValue(t ast.Type) {
+	if lit, ok := t.(*ast.TypeLiteral); ok {
+		switch lit.Type {
+		case "string":
+			c.emit(`""`)
+		case "bool":
+			c.emit("false")
+		case "float":
+			c.emit("0.0")
+		default:
+			c.emit("0")
+		}
+		return
+	}
+
+	c.emit("*new(")
+	c.compileType(t)
+	c.emit(")")
+}
@@ -1,9 +1,13 @@
 package compiler
 
 import (
+	"bytes"
 	"fmt"
 	"gloss/ast"
 	"io"
+	"maps"
+	"slices"
+	"strconv"
 	"strings"
 )
 
@@ -14,14 +18,22 @@ type Compiler interface {
 type Go struct {
 	writer      io.Writer
 	packageName string
+	imports     map[string]bool
 	indentLevel int
 	indentSize  int
+
+	// Declarations of the file by name, used to resolve enum members, union
+	// variants and the parameters of calls
+	enums  map[string]*ast.Enum
+	unions map[string]*ast.Union
+	funcs  map[string]*ast.Func
 }
 
 func NewGoCompiler(writer io.Writer) Compiler {
 	return &Go{
 		writer:      writer,
 		packageName: "main",
+		imports:     map[string]bool{},
 		indentLevel: 0,
 		indentSize:  4,
 	}
@@ -44,11 +56,122 @@ func (c *Go) emitIndent() {
 }
 
 func (c *Go) Compile(file *ast.SourceFile) {
-	c.emit("package %s\n\n", c.packageName)
-
+	// Declarations are compiled first so that the imports they use are known
+	// before the file header is written.
+	c.enums = map[string]*ast.Enum{}
+	c.unions = map[string]*ast.Union{}
+	c.funcs = map[string]*ast.Func{}
 	for _, node := range file.Declarations {
+		switch n := node.(type) {
+		case *ast.Enum:
+			c.enums[n.Name] = n
+		case *ast.Union:
+			c.unions[n.Name] = n
+		case *ast.Func:
+			if n.Receiver == nil {
+				c.funcs[n.Name] = n
+			}
+		}
+	}
+
+	out := c.writer
+	var body bytes.Buffer
+	c.writer = &body
+
+	for i, node := range file.Declarations {
+		if i > 0 {
+			c.emit("\n\n")
+		}
+		if let, ok := node.(*ast.LetStatement); ok {
+			c.compileLetStatement(let, true)
+			continue
+		}
 		c.compileNode(node)
 	}
+
+	c.writer = out
+	c.emit("package %s\n\n", c.packageName)
+	c.compileImports()
+	c.emit("%s", body.String())
+}
+
+// use records that the generated code depends on a Go package.
+func (c *Go) use(pkg string) {
+	c.imports[pkg] = true
+}
+
+func (c *Go) compileImports() {
+	if len(c.imports) == 0 {
+		return
+	}
+
+	pkgs := slices.Sorted(maps.Keys(c.imports))
+	if len(pkgs) == 1 {
+		c.emit("import %q\n\n", pkgs[0])
+		return
+	}
+
+	c.emit("import (\n")
+	for _, pkg := range pkgs {
+		c.emit("%s%q\n", strings.Repeat(" ", c.indentSize), pkg)
+	}
+	c.emit(")\n\n")
+}
+
+// capture returns the code emitted by fn rather than writing it.
+func (c *Go) capture(fn func()) string {
+	out := c.writer
+	var buf bytes.Buffer
+	c.writer = &buf
+	fn()
+	c.writer = out
+	return buf.String()
+}
+
+// enumMember returns the enum with the given member. The enum may only be
+// omitted when the name is unambiguous.
+func (c *Go) enumMember(enum, member string) *ast.Enum {
+	var found *ast.Enum
+	for _, e := range c.enums {
+		if enum != "" && e.Name != enum {
+			continue
+		}
+		for _, m := range e.Members {
+			if m.Name == member {
+				if found != nil {
+					return nil
+				}
+				found = e
+			}
+		}
+	}
+	return found
+}
+
+// variant returns the union variant with the given name. The union may only
+// be omitted when the name is unambiguous.
+func (c *Go) variant(union, name string) (*ast.Union, *ast.UnionField) {
+	var u *ast.Union
+	var field *ast.UnionField
+	for _, candidate := range c.unions {
+		if union != "" && candidate.Name != union {
+			continue
+		}
+		for _, f := range candidate.Fields {
+			if f.Name == name {
+				if field != nil {
+					return nil, nil
+				}
+				u, field = candidate, f
+			}
+		}
+	}
+	return u, field
+}
+
+// variantName is the name of the Go struct holding a union variant.
+func variantName(u *ast.Union, f *ast.UnionField) string {
+	return u.Name + f.Name
 }
 
 func (c *Go) emit(format string, args ...any) {
@@ -66,6 +189,32 @@ func (c *Go) compileNode(node ast.Node) {
 		c.compileReturnStatement(n)
 	case *ast.Func:
 		c.compileFunc(n)
+	case *ast.Enum:
+		c.compileEnum(n)
+	case *ast.Union:
+		c.compileUnion(n)
+	case *ast.Struct:
+		c.compileStruct(n)
+	case *ast.Interface:
+		c.compileInterface(n)
+	case *ast.Match:
+		c.compileMatch(n, false)
+	case *ast.LetStatement:
+		c.compileLetStatement(n, false)
+	case *ast.AssignStatement:
+		c.compileAssignStatement(n)
+	case *ast.ExpressionStatement:
+		c.compileExpressionStatement(n)
+	case *ast.Loop:
+		c.compileLoop(n)
+	case *ast.For:
+		c.compileFor(n)
+	case *ast.ForIn:
+		c.compileForIn(n)
+	case *ast.BreakStatement:
+		c.emit("break")
+	case *ast.ContinueStatement:
+		c.emit("continue")
 	default:
 	}
 }
@@ -75,20 +224,69 @@ func (c *Go) compileType(node ast.Type) {
 	case *ast.TypeIdentifier:
 		c.compileTypeIdentifier(t)
 	case *ast.TypeLiteral:
-		c.emit("%s", t.Type)
+		c.compileTypeLiteral(t)
+	case *ast.FuncType:
+		c.compileFuncType(t)
+	}
+}
+
+func (c *Go) compileFuncType(node *ast.FuncType) {
+	c.emit("func(")
+	for i, param := range node.Params {
+		if i > 0 {
+			c.emit(", ")
+		}
+		c.compileType(param)
+	}
+	c.emit(")")
+	if node.ReturnType != nil {
+		c.emit(" ")
+		c.compileType(node.ReturnType)
 	}
 }
 
+// goTypes maps builtin types whose name differs in Go.
+var goTypes = map[string]string{
+	"float": "float64",
+	"char":  "rune",
+}
+
+func (c *Go) compileTypeLiteral(node *ast.TypeLiteral) {
+	if name, ok := goTypes[node.Type]; ok {
+		c.emit("%s", name)
+		return
+	}
+	c.emit("%s", node.Type)
+}
+
+// compileTypeIdentifier compiles a named type with its type arguments, e.g.
+// Box<int> is Box[int].
 func (c *Go) compileTypeIdentifier(node *ast.TypeIdentifier) {
 	c.emit("%s", node.Name)
-	// TODO: node.Parameters
+	if len(node.Parameters) == 0 {
+		return
+	}
+
+	c.emit("[")
+	for i, p := range node.Parameters {
+		if i > 0 {
+			c.emit(", ")
+		}
+		c.compileTypeLiteral(&ast.TypeLiteral{Type: p.Name})
+	}
+	c.emit("]")
 }
 
 // Declarations
 
 func (c *Go) compileFunc(node *ast.Func) {
 	// TODO: Format func name for go conventions, e.g. pub fn sum = func Sum
-	c.emit("func %s", node.Name)
+	c.emit("func ")
+	if node.Receiver != nil {
+		_, args := typeParams(node.Receiver.Parameters)
+		c.emit("(%s %s%s) ", selfVar, node.Receiver.Name, args)
+	}
+	c.emit("%s", node.Name)
 
 	count := len(node.Params)
 
@@ -96,9 +294,7 @@ func (c *Go) compileFunc(node *ast.Func) {
 
 	c.emit("(")
 	for i := range count {
-		param := node.Params[i]
-		c.emit("%s ", param.Name)
-		c.compileType(param.Type)
+		c.compileParam(node.Params[i])
 		if i < count-1 {
 			c.emit(", ")
 		}
@@ -111,28 +307,227 @@ func (c *Go) compileFunc(node *ast.Func) {
 	}
 
 	c.emit(" ")
-	c.compileBlockStatement(node.Body)
+	c.compileBlock(node.Body, node.ReturnType != nil)
+
+	// Calls to methods are not resolved, so they have no wrapper for default
+	// values
+	if node.Receiver == nil && slices.ContainsFunc(node.Params, func(p *ast.Parameter) bool { return p.Default != nil }) {
+		c.emit("\n\n")
+		c.compileDefaults(node)
+	}
 }
 
-// Statements
+// compileFuncLiteral compiles an anonymous function to a Go func literal,
+// which captures the variables it uses.
+func (c *Go) compileFuncLiteral(node *ast.FuncLiteral) {
+	c.emit("func(")
+	for i, param := range node.Params {
+		if i > 0 {
+			c.emit(", ")
+		}
+		c.compileParam(param)
+	}
+	c.emit(")")
 
-func (c *Go) compileBlockStatement(node *ast.BlockStatement) {
+	if node.ReturnType != nil {
+		c.emit(" ")
+		c.compileType(node.ReturnType)
+	}
+
+	c.emit(" ")
+	c.compileBlock(node.Body, node.ReturnType != nil)
+}
+
+// compileParam compiles a parameter, e.g. name string or items ...string.
+func (c *Go) compileParam(param *ast.Parameter) {
+	c.emit("%s ", param.Name)
+	if param.Variadic {
+		c.emit("...")
+	}
+	c.compileType(param.Type)
+}
+
+// compileEnum compiles an enum to integer constants, prefixing each member
+// with the enum name.
+func (c *Go) compileEnum(node *ast.Enum) {
+	c.emit("type %s int\n\n", node.Name)
+	c.emit("const (")
+	c.indent()
+	for _, m := range node.Members {
+		c.emit("\n")
+		c.emitIndent()
+		c.emit("%s%s %s = %d", node.Name, m.Name, node.Name, m.IntValue)
+	}
+	c.outdent()
+	c.emit("\n)")
+}
+
+// compileUnion compiles a union to an interface implemented by a struct for
+// each variant. Payloads are held in a Value field, or as the fields of the
+// struct when the payload is a struct body.
+func (c *Go) compileUnion(node *ast.Union) {
+	params, args := typeParams(node.Parameters)
+	marker := "is" + node.Name
+
+	c.emit("type %s%s interface {\n", node.Name, params)
+	c.indent()
+	c.emitIndent()
+	c.emit("%s()\n", marker)
+	c.outdent()
+	c.emit("}")
+
+	for _, f := range node.Fields {
+		name := variantName(node, f)
+		c.emit("\n\ntype %s%s struct", name, params)
+
+		switch t := f.Type.(type) {
+		case nil:
+			c.emit("{}")
+		case *ast.StructBody:
+			c.emit(" ")
+			c.compileFields(t.Fields)
+		default:
+			c.emit(" {\n")
+			c.indent()
+			c.emitIndent()
+			c.emit("Value ")
+			c.compileType(t)
+			c.outdent()
+			c.emit("\n}")
+		}
+
+		c.emit("\n\nfunc (%s%s) %s() {}", name, args, marker)
+	}
+}
+
+// selfVar is the receiver of methods.
+const selfVar = "self"
+
+// compileStruct compiles a struct and the methods declared in its body.
+func (c *Go) compileStruct(node *ast.Struct) {
+	params, _ := typeParams(node.Params)
+	c.emit("type %s%s struct", node.Name, params)
+	if len(node.Fields) == 0 {
+		c.emit("{}")
+	} else {
+		c.emit(" ")
+		c.compileFields(node.Fields)
+	}
+
+	for _, method := range node.Methods {
+		c.emit("\n\n")
+		c.compileFunc(method)
+	}
+}
+
+func (c *Go) compileInterface(node *ast.Interface) {
+	c.emit("type %s interface", node.Name)
+	if len(node.Methods) == 0 {
+		c.emit("{}")
+		return
+	}
+
+	c.emit(" {")
+	c.indent()
+	for _, m := range node.Methods {
+		c.emit("\n")
+		c.emitIndent()
+		c.emit("%s(", m.Name)
+		for i, param := range m.Params {
+			if i > 0 {
+				c.emit(", ")
+			}
+			c.compileParam(param)
+		}
+		c.emit(")")
+		if m.ReturnType != nil {
+			c.emit(" ")
+			c.compileType(m.ReturnType)
+		}
+	}
+	c.outdent()
+	c.emit("\n}")
+}
+
+func (c *Go) compileFields(fields []*ast.StructField) {
 	c.emit("{")
 	c.indent()
-	for _, stmt := range node.Statements {
+	for _, field := range fields {
 		c.emit("\n")
 		c.emitIndent()
-		c.compileNode(stmt)
+		c.emit("%s ", field.Name)
+		c.compileType(field.Type)
 	}
 	c.outdent()
+	c.emit("\n}")
+}
+
+// typeParams returns the type parameters of a generic type as they are
+// declared, e.g. [T any], and as they are passed, e.g. [T].
+func typeParams(params []*ast.TypeParameter) (decl, args string) {
+	if len(params) == 0 {
+		return "", ""
+	}
+	names := make([]string, len(params))
+	for i, p := range params {
+		names[i] = p.Name
+	}
+	return "[" + strings.Join(names, ", ") + " any]", "[" + strings.Join(names, ", ") + "]"
+}
+
+// Statements
+
+func (c *Go) compileBlockStatement(node *ast.BlockStatement) {
+	c.compileBlock(node, false)
+}
+
+// compileBlock compiles a block, returning its value when ret is set.
+func (c *Go) compileBlock(node *ast.BlockStatement, ret bool) {
+	c.emit("{")
+	c.indent()
+	c.compileStatements(node.Statements, ret)
+	c.outdent()
 	if len(node.Statements) > 0 {
 		c.emit("\n")
+		c.emitIndent()
 	}
 
 	c.emit("}")
 }
 
+// compileStatements compiles the statements of a block, each on a new line.
+// When ret is set the trailing expression is the value of the block and is
+// returned, e.g. the body of fn double(x: int) int { x * 2 }.
+func (c *Go) compileStatements(stmts []ast.Node, ret bool) {
+	for i, stmt := range stmts {
+		c.emit("\n")
+		c.emitIndent()
+		if !ret || i < len(stmts)-1 {
+			c.compileNode(stmt)
+			continue
+		}
+
+		switch n := stmt.(type) {
+		case *ast.Match:
+			c.compileMatch(n, true)
+		case *ast.ExpressionStatement:
+			c.compileReturnStatement(&ast.ReturnStatement{Value: n.Expression})
+		default:
+			c.compileNode(stmt)
+		}
+	}
+}
+
+func (c *Go) compileExpressionStatement(node *ast.ExpressionStatement) {
+	c.compileExpression(node.Expression)
+}
+
 func (c *Go) compileReturnStatement(node *ast.ReturnStatement) {
+	if m, ok := node.Value.(*ast.Match); ok {
+		c.compileMatch(m, true)
+		return
+	}
+
 	c.emit("return")
 	if node.Value != nil {
 		c.emit(" ")
@@ -140,6 +535,142 @@ func (c *Go) compileReturnStatement(node *ast.ReturnStatement) {
 	}
 }
 
+// compileLetStatement compiles a variable declaration. Variables with an
+// explicit type, and those declared at the top level of a file where Go does
+// not allow :=, are declared with var.
+func (c *Go) compileLetStatement(node *ast.LetStatement, topLevel bool) {
+	if m, ok := node.Value.(*ast.Match); ok && node.Type != nil {
+		// The value is a function returning the type of the variable
+		c.compileIdentifier(node.Name)
+		if topLevel {
+			c.emit(" = ")
+		} else {
+			c.emit(" := ")
+		}
+		c.compileMatchValue(m, node.Type)
+		return
+	}
+
+	if node.Type == nil && !topLevel {
+		c.compileIdentifier(node.Name)
+		c.emit(" := ")
+		c.compileExpression(node.Value)
+		return
+	}
+
+	c.emit("var ")
+	c.compileIdentifier(node.Name)
+	if node.Type != nil {
+		c.emit(" ")
+		c.compileType(node.Type)
+	}
+	if node.Value != nil {
+		c.emit(" = ")
+		c.compileExpression(node.Value)
+	}
+}
+
+func (c *Go) compileAssignStatement(node *ast.AssignStatement) {
+	c.compileExpression(node.Target)
+	c.emit(" %s ", node.Operator)
+	c.compileExpression(node.Value)
+}
+
+func (c *Go) compileLoop(node *ast.Loop) {
+	c.emit("for ")
+	c.compileBlockStatement(node.Body)
+}
+
+func (c *Go) compileFor(node *ast.For) {
+	c.emit("for ")
+	switch {
+	case node.Init != nil || node.Post != nil:
+		c.compileForInit(node.Init)
+		c.emit("; ")
+		c.compileExpression(node.Condition)
+		c.emit("; ")
+		c.compileNode(node.Post)
+		c.emit(" ")
+	case node.Condition != nil:
+		c.compileExpression(node.Condition)
+		c.emit(" ")
+	}
+	c.compileBlockStatement(node.Body)
+}
+
+// compileForInit compiles the init statement of a loop. Go does not allow var
+// declarations there, so variables with an explicit type convert their value
+// to it, e.g. let mut i: int = 0 is i := int(0).
+func (c *Go) compileForInit(node ast.Node) {
+	let, ok := node.(*ast.LetStatement)
+	if !ok || let.Type == nil {
+		c.compileNode(node)
+		return
+	}
+	if _, ok := let.Value.(*ast.Match); ok {
+		c.compileNode(node)
+		return
+	}
+
+	c.compileIdentifier(let.Name)
+	c.emit(" := ")
+	if let.Value == nil {
+		c.compileZeroValue(let.Type)
+		return
+	}
+
+	if _, ok := let.Type.(*ast.FuncType); ok {
+		c.emit("(")
+		c.compileType(let.Type)
+		c.emit(")")
+	} else {
+		c.compileType(let.Type)
+	}
+	c.emit("(")
+	c.compileExpression(let.Value)
+	c.emit(")")
+}
+
+func (c *Go) compileForIn(node *ast.ForIn) {
+	c.emit("for ")
+	c.compileForInClause(node.Key, node.Item, node.Iterable)
+	c.emit(" ")
+	c.compileBlockStatement(node.Body)
+}
+
+// compileForInClause compiles the clause of a loop over the items of a
+// collection, e.g. x in xs is _, x := range xs. Ranges count from their start
+// to their end, e.g. i in 0..10 is i := 0; i < 10; i++.
+func (c *Go) compileForInClause(key, item *ast.Identifier, iterable ast.Expression) {
+	if r, ok := iterable.(*ast.RangeExpression); ok {
+		c.compileIdentifier(item)
+		c.emit(" := ")
+		c.compileExpression(r.Start)
+		c.emit("; ")
+		c.compileIdentifier(item)
+		if r.Inclusive {
+			c.emit(" <= ")
+		} else {
+			c.emit(" < ")
+		}
+		c.compileExpression(r.End)
+		c.emit("; ")
+		c.compileIdentifier(item)
+		c.emit("++")
+		return
+	}
+
+	if key != nil {
+		c.compileIdentifier(key)
+	} else {
+		c.emit("_")
+	}
+	c.emit(", ")
+	c.compileIdentifier(item)
+	c.emit(" := range ")
+	c.compileExpression(iterable)
+}
+
 // Expressions
 
 func (c *Go) compileExpression(exp ast.Expression) {
@@ -150,8 +681,38 @@ func (c *Go) compileExpression(exp ast.Expression) {
 		c.compileUnaryExpression(t)
 	case *ast.IntegerLiteral:
 		c.compileIntegerLiteral(t)
+	case *ast.FloatLiteral:
+		c.compileFloatLiteral(t)
+	case *ast.StringLiteral:
+		c.compileStringLiteral(t)
+	case *ast.TemplateLiteral:
+		c.compileTemplateLiteral(t)
+	case *ast.CharLiteral:
+		c.compileCharLiteral(t)
+	case *ast.Boolean:
+		c.compileBoolean(t)
+	case *ast.TypeConversion:
+		c.compileTypeConversion(t)
 	case *ast.Identifier:
 		c.compileIdentifier(t)
+	case *ast.MemberExpression:
+		c.compileMemberExpression(t)
+	case *ast.IndexExpression:
+		c.compileIndexExpression(t)
+	case *ast.ParenExpression:
+		c.compileParenExpression(t)
+	case *ast.Match:
+		c.compileMatchValue(t, nil)
+	case *ast.CallExpression:
+		c.compileCallExpression(t)
+	case *ast.FuncLiteral:
+		c.compileFuncLiteral(t)
+	case *ast.LabeledArgument:
+		// Labels of arguments to functions which are not declared in the file
+		// are reported by the checker
+		c.compileExpression(t.Value)
+	case *ast.Element:
+		c.compileElement(t)
 	}
 }
 
@@ -170,6 +731,120 @@ func (c *Go) compileIdentifier(node *ast.Identifier) {
 	c.emit("%s", node.Name)
 }
 
+func (c *Go) compileParenExpression(node *ast.ParenExpression) {
+	c.emit("(")
+	c.compileExpression(node.Expression)
+	c.emit(")")
+}
+
+// variantOf resolves the union variant named by an expression, e.g.
+// Shape.Circle or Circle.
+func (c *Go) variantOf(exp ast.Expression) (*ast.Union, *ast.UnionField) {
+	switch e := exp.(type) {
+	case *ast.Identifier:
+		return c.variant("", e.Name)
+	case *ast.MemberExpression:
+		if obj, ok := e.Object.(*ast.Identifier); ok && c.unions[obj.Name] != nil {
+			return c.variant(obj.Name, e.Property.Name)
+		}
+	}
+	return nil, nil
+}
+
+// compileVariant constructs a union variant, e.g. Shape.Circle(1.0) is
+// ShapeCircle{Value: 1.0}. Arguments to struct payloads are its fields.
+func (c *Go) compileVariant(u *ast.Union, f *ast.UnionField, args []ast.Expression) {
+	c.emit("%s{", variantName(u, f))
+	switch f.Type.(type) {
+	case nil:
+	case *ast.StructBody:
+		for i, arg := range args {
+			if i > 0 {
+				c.emit(", ")
+			}
+			c.compileExpression(arg)
+		}
+	default:
+		if len(args) > 0 {
+			c.emit("Value: ")
+			c.compileExpression(args[0])
+		}
+	}
+	c.emit("}")
+}
+
+func (c *Go) compileMemberExpression(node *ast.MemberExpression) {
+	if obj, ok := node.Object.(*ast.Identifier); ok {
+		if e := c.enums[obj.Name]; e != nil && c.enumMember(e.Name, node.Property.Name) != nil {
+			c.emit("%s%s", e.Name, node.Property.Name)
+			return
+		}
+		if u, f := c.variantOf(node); f != nil {
+			c.compileVariant(u, f, nil)
+			return
+		}
+	}
+
+	c.compileExpression(node.Object)
+	c.emit(".")
+	c.compileIdentifier(node.Property)
+}
+
+func (c *Go) compileIndexExpression(node *ast.IndexExpression) {
+	c.compileExpression(node.Object)
+	c.emit("[")
+	c.compileExpression(node.Index)
+	c.emit("]")
+}
+
 func (c *Go) compileIntegerLiteral(node *ast.IntegerLiteral) {
 	c.emit("%d", node.Value)
 }
+
+func (c *Go) compileFloatLiteral(node *ast.FloatLiteral) {
+	lit := strconv.FormatFloat(node.Value, 'g', -1, 64)
+	// Keep whole numbers typed as floats, e.g. 1.0 rather than 1
+	if !strings.ContainsAny(lit, ".eIN") {
+		lit += ".0"
+	}
+	c.emit("%s", lit)
+}
+
+func (c *Go) compileStringLiteral(node *ast.StringLiteral) {
+	c.emit("%s", strconv.Quote(node.Value))
+}
+
+// compileTemplateLiteral concatenates the template parts, formatting each
+// interpolated value with fmt.Sprint.
+func (c *Go) compileTemplateLiteral(node *ast.TemplateLiteral) {
+	for i, part := range node.Parts {
+		if i > 0 {
+			c.emit(" + ")
+		}
+
+		if str, ok := part.(*ast.StringLiteral); ok {
+			c.compileStringLiteral(str)
+			continue
+		}
+
+		c.use("fmt")
+		c.emit("fmt.Sprint(")
+		c.compileExpression(part)
+		c.emit(")")
+	}
+}
+
+func (c *Go) compileCharLiteral(node *ast.CharLiteral) {
+	c.emit("%s", strconv.QuoteRune(node.Value))
+}
+
+func (c *Go) compileBoolean(node *ast.Boolean) {
+	c.emit("%t", node.Value)
+}
+
+func (c *Go) compileTypeConversion(node *ast.TypeConversion) {
+	c.compileTypeLiteral(node.Type)
+	c.emit("(")
+	c.compileExpression(node.Value)
+	c.emit(")")
+}
@@ -0,0 +1,224 @@
+package compiler
+
+import (
+	"gloss/ast"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// compileElement compiles an element to a runtime node. Lowercase elements
+// are html tags, capitalised elements are calls to component functions and
+// fragments group their children, e.g.
+//
+//	<div id="app">Hi</div>  h("div", Attributes{{Key: "id", Value: "app"}}, Text("Hi"))
+//	<Button>Hi</Button>     Button(nil, Text("Hi"))
+//	<>Hi</>                 Fragment(Text("Hi"))
+func (c *Go) compileElement(node *ast.Element) {
+	switch {
+	case node.Name == "":
+		c.emit("Fragment(")
+	case isComponent(node.Name):
+		c.emit("%s(", node.Name)
+		c.compileAttributes(node.Attributes)
+	default:
+		c.emit("h(%s, ", strconv.Quote(node.Name))
+		c.compileAttributes(node.Attributes)
+	}
+
+	if len(node.Children) == 0 {
+		c.emit(")")
+		return
+	}
+
+	if node.Name != "" {
+		c.emit(",")
+	}
+
+	c.indent()
+	for _, child := range node.Children {
+		c.emit("\n")
+		c.emitIndent()
+		c.compileElementChild(child)
+		c.emit(",")
+	}
+	c.outdent()
+
+	c.emit("\n")
+	c.emitIndent()
+	c.emit(")")
+}
+
+func (c *Go) compileElementChild(node ast.Expression) {
+	switch n := node.(type) {
+	case *ast.StringLiteral:
+		c.emit("Text(")
+		c.compileStringLiteral(n)
+		c.emit(")")
+	case *ast.Element:
+		c.compileElement(n)
+	case *ast.ElementIf:
+		c.compileElementIf(n)
+	case *ast.ElementFor:
+		c.compileElementFor(n)
+	default:
+		c.emit("Value(")
+		c.compileExpression(n)
+		c.emit(")")
+	}
+}
+
+// compileChildren compiles element children to a single node, wrapping
+// several children in a fragment.
+func (c *Go) compileChildren(children []ast.Expression) {
+	switch len(children) {
+	case 0:
+		c.emit("nil")
+	case 1:
+		c.compileElementChild(children[0])
+	default:
+		c.compileElement(&ast.Element{Children: children})
+	}
+}
+
+func (c *Go) compileElementIf(node *ast.ElementIf) {
+	c.emit("If(")
+	c.compileExpression(node.Condition)
+	c.emit(", ")
+	c.compileChildren(node.Then)
+	c.emit(", ")
+
+	switch e := node.Else.(type) {
+	case *ast.ElementIf:
+		c.compileElementIf(e)
+	case *ast.Element:
+		c.compileChildren(e.Children)
+	default:
+		c.emit("nil")
+	}
+	c.emit(")")
+}
+
+// compileElementFor compiles a for loop within element children to a
+// sequence of nodes, yielding the body for each item. A body which is a
+// single keyed element yields the key with each node, e.g.
+// {for item in items { <li key={item.id}>{item.name}</li> }}.
+func (c *Go) compileElementFor(node *ast.ElementFor) {
+	var key ast.Expression
+	if len(node.Body) == 1 {
+		if el, ok := node.Body[0].(*ast.Element); ok {
+			key = el.Key
+		}
+	}
+
+	if key != nil {
+		c.emit("Each(func(yield func(string, Node) bool) {\n")
+	} else {
+		c.emit("List(func(yield func(Node) bool) {\n")
+	}
+	c.indent()
+	c.emitIndent()
+	c.emit("for ")
+	c.compileForInClause(node.Key, node.Item, node.Iterable)
+	c.emit(" {\n")
+
+	c.indent()
+	c.emitIndent()
+	c.emit("if !yield(")
+	if key != nil {
+		c.compileAttributeValue(key)
+		c.emit(", ")
+	}
+	c.compileChildren(node.Body)
+	c.emit(") {\n")
+	c.indent()
+	c.emitIndent()
+	c.emit("return\n")
+	c.outdent()
+	c.emitIndent()
+	c.emit("}\n")
+	c.outdent()
+
+	c.emitIndent()
+	c.emit("}\n")
+	c.outdent()
+	c.emitIndent()
+	c.emit("})")
+}
+
+// compileAttributes compiles element attributes to an Attributes value.
+// Spread attributes are merged with the surrounding attributes so that later
+// attributes take precedence, e.g. <a {...props} href="/" />.
+func (c *Go) compileAttributes(attrs []*ast.Attribute) {
+	if len(attrs) == 0 {
+		c.emit("nil")
+		return
+	}
+
+	if !slices.ContainsFunc(attrs, func(a *ast.Attribute) bool { return a.Spread }) {
+		c.compileAttributeList(attrs)
+		return
+	}
+
+	c.emit("MergeAttributes(")
+	var named []*ast.Attribute
+	for i, attr := range attrs {
+		if !attr.Spread {
+			named = append(named, attr)
+			continue
+		}
+
+		if len(named) > 0 {
+			c.compileAttributeList(named)
+			c.emit(", ")
+			named = nil
+		}
+
+		c.compileExpression(attr.Value)
+		if i < len(attrs)-1 {
+			c.emit(", ")
+		}
+	}
+	if len(named) > 0 {
+		c.compileAttributeList(named)
+	}
+	c.emit(")")
+}
+
+func (c *Go) compileAttributeList(attrs []*ast.Attribute) {
+	c.emit("Attributes{")
+	for i, attr := range attrs {
+		if i > 0 {
+			c.emit(", ")
+		}
+		c.emit("{Key: %s, Value: ", strconv.Quote(attr.Name))
+		c.compileAttributeValue(attr.Value)
+		c.emit("}")
+	}
+	c.emit("}")
+}
+
+// compileAttributeValue compiles an attribute value or element key to a
+// string. Attributes without a value, e.g. disabled, are empty.
+func (c *Go) compileAttributeValue(value ast.Expression) {
+	switch v := value.(type) {
+	case nil:
+		c.emit(`""`)
+	case *ast.StringLiteral, *ast.TemplateLiteral:
+		c.compileExpression(v)
+	default:
+		c.use("fmt")
+		c.emit("fmt.Sprint(")
+		c.compileExpression(v)
+		c.emit(")")
+	}
+}
+
+// isComponent reports whether an element name refers to a component rather
+// than an html tag, e.g. Button or ui.Button.
+func isComponent(name string) bool {
+	r, _ := utf8.DecodeRuneInString(name)
+	return unicode.IsUpper(r) || strings.Contains(name, ".")
+}
@@ -6,6 +6,8 @@ import (
 	"gloss/parser"
 
 	"bytes"
+	goparser "go/parser"
+	gotoken "go/token"
 	"io"
 	"testing"
 
@@ -48,7 +50,7 @@ func TestCompilerMainFunc(t *testing.T) {
 
 // TODO: TABS VS SPACES
 func TestCompiler(t *testing.T) {
-	input := `fn sum(a int, b int) int {
+	input := `fn sum(a: int, b: int) int {
 	return a + b
 	}`
 	want := `package main
@@ -58,3 +60,847 @@ func sum(a int, b int) int {
 }`
 	assertCompileResult(t, input, want)
 }
+
+func TestCompilerNumericLiterals(t *testing.T) {
+	input := `fn scale(x: float) float {
+	return x * 2.0 + -0.5e1 - 0x10
+	}`
+	want := `package main
+
+func scale(x float64) float64 {
+    return x * 2.0 + -5.0 - 16
+}`
+	assertCompileResult(t, input, want)
+}
+
+func TestCompilerStrings(t *testing.T) {
+	input := "fn greet(name: string, age: int) string {\n" +
+		"return `${name} is ${age} years old\\n`\n" +
+		"}\n" +
+		"fn newline() string { return \"a\\tb\\n\" }"
+	want := `package main
+
+import "fmt"
+
+func greet(name string, age int) string {
+    return fmt.Sprint(name) + " is " + fmt.Sprint(age) + " years old\\n"
+}
+
+func newline() string {
+    return "a\tb\n"
+}`
+	assertCompileResult(t, input, want)
+}
+
+func TestCompilerChars(t *testing.T) {
+	input := `fn next(c: char) char {
+	return char(int(c) + 1)
+	}
+	fn isTab(c: char) bool { return c == '\t' }
+	fn toString(c: char) string { return string(c) }`
+	want := `package main
+
+func next(c rune) rune {
+    return rune(int(c) + 1)
+}
+
+func isTab(c rune) bool {
+    return c == '\t'
+}
+
+func toString(c rune) string {
+    return string(c)
+}`
+	assertCompileResult(t, input, want)
+}
+
+func TestCompilerLoops(t *testing.T) {
+	input := `fn loops(n: int) {
+	loop { break }
+	for n > 0 { continue }
+	for i = 0; i < n; i = i + 1 {}
+	for ;; {}
+	for x in xs {}
+	for key, value in m { let v = value }
+	for i in 0..n {}
+	for i in 1..=n {}
+	}`
+	want := `package main
+
+func loops(n int) {
+    for {
+        break
+    }
+    for n > 0 {
+        continue
+    }
+    for i := 0; i < n; i = i + 1 {}
+    for {}
+    for _, x := range xs {}
+    for key, value := range m {
+        v := value
+    }
+    for i := 0; i < n; i++ {}
+    for i := 1; i <= n; i++ {}
+}`
+	assertCompileResult(t, input, want)
+}
+
+func TestCompilerAssignment(t *testing.T) {
+	input := `fn sum(xs: Numbers) int {
+	let mut total = 0
+	for i in 0..10 {
+		total += xs[i]
+		xs[i] = 0
+		p.x -= 1
+	}
+	total = total * 2
+	return total
+	}`
+	want := `package main
+
+func sum(xs Numbers) int {
+    total := 0
+    for i := 0; i < 10; i++ {
+        total += xs[i]
+        xs[i] = 0
+        p.x -= 1
+    }
+    total = total * 2
+    return total
+}`
+	assertCompileResult(t, input, want)
+}
+
+func TestCompilerLetTypes(t *testing.T) {
+	input := `let greeting: string = "hi"
+let count = 0
+fn f() float {
+	let mut x: float = 1
+	let mut y: int
+	y = 2
+	let z = x * float(y)
+	return z
+	}`
+	want := `package main
+
+var greeting string = "hi"
+
+var count = 0
+
+func f() float64 {
+    var x float64 = 1
+    var y int
+    y = 2
+    z := x * float64(y)
+    return z
+}`
+	assertCompileResult(t, input, want)
+}
+
+func TestCompilerForTypedInit(t *testing.T) {
+	input := `fn f(n: int) int {
+	let mut total = 0
+	for let mut i: int = 0; i < n; i = i + 1 {
+		total += i
+	}
+	for let mut x: float; x < 1.0; x += 0.5 {}
+	return total
+}`
+	want := `package main
+
+func f(n int) int {
+    total := 0
+    for i := int(0); i < n; i = i + 1 {
+        total += i
+    }
+    for x := 0.0; x < 1.0; x += 0.5 {}
+    return total
+}`
+	assertCompileResult(t, input, want)
+
+	// Go does not allow var declarations in the init statement of a loop
+	if _, err := goparser.ParseFile(gotoken.NewFileSet(), "main.go", want, 0); err != nil {
+		t.Errorf("go/parser.ParseFile() error: %v", err)
+	}
+}
+
+func TestCompilerTrailingExpression(t *testing.T) {
+	input := `fn main() {
+	fmt.Println("hi")
+	double(2)
+	}
+fn double(x: int) int {
+	let y = x
+	y * 2
+	}
+fn view() Node { <p>Hi</p> }
+fn name(c: Color) string {
+	match c {
+		Color.Red => {
+			log("red")
+			"red"
+		}
+		_ => "other"
+	}
+	}
+enum Color { Red }`
+	want := `package main
+
+func main() {
+    fmt.Println("hi")
+    double(2)
+}
+
+func double(x int) int {
+    y := x
+    return y * 2
+}
+
+func view() Node {
+    return h("p", nil,
+        Text("Hi"),
+    )
+}
+
+func name(c Color) string {
+    switch c {
+    case ColorRed:
+        log("red")
+        return "red"
+    default:
+        return "other"
+    }
+}
+
+type Color int
+
+const (
+    ColorRed Color = 0
+)`
+	assertCompileResult(t, input, want)
+}
+
+func TestCompilerLabeledArguments(t *testing.T) {
+	input := `fn link(href: string, text: string, title: string) Node {}
+fn main() {
+	let title = "Home"
+	link(text: "Home", href: "/", ~title)
+	link("/", title:, text: "Home")
+	fmt.Println(a: 1)
+	}`
+	want := `package main
+
+func link(href string, text string, title string) Node {}
+
+func main() {
+    title := "Home"
+    link("/", "Home", title)
+    link("/", "Home", title)
+    fmt.Println(1)
+}`
+	assertCompileResult(t, input, want)
+}
+
+func TestCompilerDefaultArguments(t *testing.T) {
+	input := `fn link(href: string, id?: string, text: string = "Home", size: int = 1) Node {}
+fn main() {
+	link("/")
+	link("/", size: 2)
+	link("/", id: "a", text: "Away", size: 2)
+	}`
+	want := `package main
+
+func link(href string, id string, text string, size int) Node {}
+
+func linkDefaults(href string, id string, text string, size int, in int) Node {
+    if in&4 == 0 {
+        text = "Home"
+    }
+    if in&8 == 0 {
+        size = 1
+    }
+    return link(href, id, text, size)
+}
+
+func main() {
+    linkDefaults("/", "", "", 0, 1)
+    linkDefaults("/", "", "", 2, 9)
+    link("/", "a", "Away", 2)
+}`
+	assertCompileResult(t, input, want)
+}
+
+func TestCompilerVariadicParams(t *testing.T) {
+	input := `fn join(sep: string, items: ...string) string {}
+fn list(class: string = "list", items: ...Node) Node {}
+fn main() {
+	join(", ")
+	join(", ", "a", "b")
+	list(class: "menu")
+	list("menu", a, b)
+	}`
+	want := `package main
+
+func join(sep string, items ...string) string {}
+
+func list(class string, items ...Node) Node {}
+
+func listDefaults(class string, in int, items ...Node) Node {
+    if in&1 == 0 {
+        class = "list"
+    }
+    return list(class, items...)
+}
+
+func main() {
+    join(", ")
+    join(", ", "a", "b")
+    list("menu")
+    list("menu", a, b)
+}`
+	assertCompileResult(t, input, want)
+}
+
+func TestCompilerClosures(t *testing.T) {
+	input := `fn apply(n: int, f: fn(int) int) int {
+	f(n)
+}
+fn adder(by: int) fn(int) int {
+	fn(n: int) int { n + by }
+}
+fn main() {
+	let mut total = 0
+	let add = fn(n: int) {
+		total += n
+	}
+	add(1)
+	apply(2, adder(3))
+	adder(1)(2)
+	}`
+	want := `package main
+
+func apply(n int, f func(int) int) int {
+    return f(n)
+}
+
+func adder(by int) func(int) int {
+    return func(n int) int {
+        return n + by
+    }
+}
+
+func main() {
+    total := 0
+    add := func(n int) {
+        total += n
+    }
+    add(1)
+    apply(2, adder(3))
+    adder(1)(2)
+}`
+	assertCompileResult(t, input, want)
+}
+
+func TestCompilerMethods(t *testing.T) {
+	input := `struct Rect {
+	w: int
+	h: int
+	fn area() int {
+		self.w * self.h
+	}
+}
+struct Box<T> {
+	value: T
+	fn get() T { self.value }
+}
+fn scale(by: int) for Rect int {
+	self.area() * by
+}`
+	want := `package main
+
+type Rect struct {
+    w int
+    h int
+}
+
+func (self Rect) area() int {
+    return self.w * self.h
+}
+
+type Box[T any] struct {
+    value T
+}
+
+func (self Box[T]) get() T {
+    return self.value
+}
+
+func (self Rect) scale(by int) int {
+    return self.area() * by
+}`
+	assertCompileResult(t, input, want)
+}
+
+func TestCompilerInterfaces(t *testing.T) {
+	input := `interface Printer {
+	write(line: string)
+	flush() bool
+}
+interface Any {}
+struct Logger {
+	out: Printer
+}
+fn log(p: Printer, line: string) {
+	p.write(line)
+}`
+	want := `package main
+
+type Printer interface {
+    write(line string)
+    flush() bool
+}
+
+type Any interface{}
+
+type Logger struct {
+    out Printer
+}
+
+func log(p Printer, line string) {
+    p.write(line)
+}`
+	assertCompileResult(t, input, want)
+}
+
+func TestCompilerGenericTypes(t *testing.T) {
+	input := `struct Pair<K, V> { key: K, value: V }
+fn first(p: Pair<string, float>) string {
+	p.key
+}`
+	want := `package main
+
+type Pair[K, V any] struct {
+    key K
+    value V
+}
+
+func first(p Pair[string, float64]) string {
+    return p.key
+}`
+	assertCompileResult(t, input, want)
+}
+
+func TestCompilerElements(t *testing.T) {
+	input := `fn App(name: string, count: int) Node {
+	return <div id="app" data-count={count} hidden>
+		Hello, {name}!
+		<Button label="Go"><br/></Button>
+	</div>
+	}`
+	want := `package main
+
+import "fmt"
+
+func App(name string, count int) Node {
+    return h("div", Attributes{{Key: "id", Value: "app"}, {Key: "data-count", Value: fmt.Sprint(count)}, {Key: "hidden", Value: ""}},
+        Text("Hello, "),
+        Value(name),
+        Text("!"),
+        Button(Attributes{{Key: "label", Value: "Go"}},
+            h("br", nil),
+        ),
+    )
+}`
+	assertCompileResult(t, input, want)
+}
+
+func TestCompilerFragmentsAndSpreads(t *testing.T) {
+	input := `fn Link(props: Attributes) Node {
+	return <><a {...props} href="/">Home</a><a {...props} /></>
+	}`
+	want := `package main
+
+func Link(props Attributes) Node {
+    return Fragment(
+        h("a", MergeAttributes(props, Attributes{{Key: "href", Value: "/"}}),
+            Text("Home"),
+        ),
+        h("a", MergeAttributes(props)),
+    )
+}`
+	assertCompileResult(t, input, want)
+}
+
+func TestCompilerElementControlFlow(t *testing.T) {
+	input := `fn Clock(time: int, items: Items) Node {
+	return <div>
+		{if time > 0 { Current time: {time} } else { Unknown }}
+		<ul>{for item in items { <li>{item}</li> }}</ul>
+	</div>
+	}`
+	want := `package main
+
+func Clock(time int, items Items) Node {
+    return h("div", nil,
+        If(time > 0, Fragment(
+            Text("Current time: "),
+            Value(time),
+        ), Text("Unknown")),
+        h("ul", nil,
+            List(func(yield func(Node) bool) {
+                for _, item := range items {
+                    if !yield(h("li", nil,
+                        Value(item),
+                    )) {
+                        return
+                    }
+                }
+            }),
+        ),
+    )
+}`
+	assertCompileResult(t, input, want)
+}
+
+func TestCompilerKeyedList(t *testing.T) {
+	input := `fn Users(users: UserList) Node {
+	return <ul>{for user in users { <li key={user.id}>{user.name}</li> }}</ul>
+	}`
+	want := `package main
+
+import "fmt"
+
+func Users(users UserList) Node {
+    return h("ul", nil,
+        Each(func(yield func(string, Node) bool) {
+            for _, user := range users {
+                if !yield(fmt.Sprint(user.id), h("li", nil,
+                    Value(user.name),
+                )) {
+                    return
+                }
+            }
+        }),
+    )
+}`
+	assertCompileResult(t, input, want)
+}
+
+func TestCompilerMatchEnum(t *testing.T) {
+	input := `enum Color { Red, Green = 5, Blue }
+
+fn name(c: Color) string {
+	return match c {
+		Color.Red => "red",
+		Color.Green => "green",
+		_ => "other",
+	}
+}
+
+fn log(c: Color) {
+	match c {
+		Color.Blue => print("blue")
+		other => print(other)
+	}
+}`
+	want := `package main
+
+type Color int
+
+const (
+    ColorRed Color = 0
+    ColorGreen Color = 5
+    ColorBlue Color = 6
+)
+
+func name(c Color) string {
+    switch c {
+    case ColorRed:
+        return "red"
+    case ColorGreen:
+        return "green"
+    default:
+        return "other"
+    }
+}
+
+func log(c Color) {
+    switch match := c; match {
+    case ColorBlue:
+        print("blue")
+    default:
+        other := match
+        print(other)
+    }
+}`
+	assertCompileResult(t, input, want)
+}
+
+func TestCompilerMatchDuplicateCases(t *testing.T) {
+	input := `enum Color { Red, Green }
+
+fn name(c: Color) string {
+	match c {
+		Color.Red => "red",
+		Color.Red => "again",
+		Color.Green => "green",
+	}
+}`
+	want := `package main
+
+type Color int
+
+const (
+    ColorRed Color = 0
+    ColorGreen Color = 1
+)
+
+func name(c Color) string {
+    switch c {
+    case ColorRed:
+        return "red"
+    case ColorGreen:
+        return "green"
+    }
+    panic("unreachable")
+}`
+	assertCompileResult(t, input, want)
+}
+
+func TestCompilerMatchNestedUnion(t *testing.T) {
+	input := `union Result {
+	Ok(int),
+	Err(string),
+}
+
+union Option {
+	Some(Result),
+	None,
+}
+
+fn unwrap(o: Option) int {
+	return match o {
+		Some(Ok(0)) => -1,
+		Some(Ok(n)) => n,
+		Option.None => 0,
+	}
+}`
+	want := `package main
+
+type Result interface {
+    isResult()
+}
+
+type ResultOk struct {
+    Value int
+}
+
+func (ResultOk) isResult() {}
+
+type ResultErr struct {
+    Value string
+}
+
+func (ResultErr) isResult() {}
+
+type Option interface {
+    isOption()
+}
+
+type OptionSome struct {
+    Value Result
+}
+
+func (OptionSome) isOption() {}
+
+type OptionNone struct{}
+
+func (OptionNone) isOption() {}
+
+func unwrap(o Option) int {
+    switch match := o.(type) {
+    case OptionSome:
+        switch {
+        case func() bool { _, ok := match.Value.(ResultOk); return ok }() && match.Value.(ResultOk).Value == 0:
+            return -1
+        case func() bool { _, ok := match.Value.(ResultOk); return ok }():
+            n := match.Value.(ResultOk).Value
+            return n
+        }
+    case OptionNone:
+        return 0
+    }
+    panic("unreachable")
+}`
+	assertCompileResult(t, input, want)
+}
+
+func TestCompilerMatchUnitVariants(t *testing.T) {
+	input := `enum Color { Red, Green }
+union Shape { Circle(float), Rect, Empty }
+union Option { Some(Shape), None }
+
+fn f(o: Option) int {
+	match o {
+		Some(Circle(_)) => 1,
+		None => 0,
+	}
+}
+
+fn g(c: Color) int {
+	match c {
+		Red => 1,
+		other => 0,
+	}
+}`
+	want := `package main
+
+type Color int
+
+const (
+    ColorRed Color = 0
+    ColorGreen Color = 1
+)
+
+type Shape interface {
+    isShape()
+}
+
+type ShapeCircle struct {
+    Value float64
+}
+
+func (ShapeCircle) isShape() {}
+
+type ShapeRect struct{}
+
+func (ShapeRect) isShape() {}
+
+type ShapeEmpty struct{}
+
+func (ShapeEmpty) isShape() {}
+
+type Option interface {
+    isOption()
+}
+
+type OptionSome struct {
+    Value Shape
+}
+
+func (OptionSome) isOption() {}
+
+type OptionNone struct{}
+
+func (OptionNone) isOption() {}
+
+func f(o Option) int {
+    switch match := o.(type) {
+    case OptionSome:
+        switch {
+        case func() bool { _, ok := match.Value.(ShapeCircle); return ok }():
+            return 1
+        }
+    case OptionNone:
+        return 0
+    }
+    panic("unreachable")
+}
+
+func g(c Color) int {
+    switch c {
+    case ColorRed:
+        return 1
+    default:
+        return 0
+    }
+}`
+	assertCompileResult(t, input, want)
+}
+
+func TestCompilerMatchValue(t *testing.T) {
+	input := `fn f(c: int) int {
+	let x = match c {
+		1 => 2,
+		other => 0,
+	}
+	let y: string = match c {
+		n => "many",
+	}
+	x + len(y)
+	}`
+	want := `package main
+
+func f(c int) int {
+    x := func() int {
+        switch c {
+        case 1:
+            return 2
+        default:
+            return 0
+        }
+    }()
+    y := func() string {
+        switch c {
+        default:
+            return "many"
+        }
+    }()
+    return x + len(y)
+}`
+	assertCompileResult(t, input, want)
+}
+
+func TestCompilerMatchTuple(t *testing.T) {
+	input := `union Shape {
+	Circle(float),
+	Rect({ w: int, h: int }),
+}
+
+fn describe(s: Shape, scale: int) string {
+	return match (s, scale) {
+		(Rect(_, 1), 0) => "flat",
+		(Circle(_), 1) => "round",
+		_ => "other",
+	}
+}
+
+fn make() Shape {
+	return Shape.Circle(2.0)
+}`
+	want := `package main
+
+type Shape interface {
+    isShape()
+}
+
+type ShapeCircle struct {
+    Value float64
+}
+
+func (ShapeCircle) isShape() {}
+
+type ShapeRect struct {
+    w int
+    h int
+}
+
+func (ShapeRect) isShape() {}
+
+func describe(s Shape, scale int) string {
+    switch match0, match1 := s, scale; {
+    case func() bool { _, ok := match0.(ShapeRect); return ok }() && match0.(ShapeRect).h == 1 && match1 == 0:
+        return "flat"
+    case func() bool { _, ok := match0.(ShapeCircle); return ok }() && match1 == 1:
+        return "round"
+    default:
+        return "other"
+    }
+}
+
+func make() Shape {
+    return ShapeCircle{Value: 2.0}
+}`
+	assertCompileResult(t, input, want)
+}
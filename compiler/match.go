@@ -0,0 +1,533 @@
+package compiler
+
+import (
+	"fmt"
+	"gloss/ast"
+	"reflect"
+	"strings"
+)
+
+// matchVar holds the subject of a match in the generated switch. It is a
+// keyword in gloss, so it cannot clash with the names bound by patterns.
+const matchVar = "match"
+
+// matchCase is an arm of a match with the Go conditions under which its
+// pattern matches and the variables it binds.
+type matchCase struct {
+	conds []string
+	binds []string
+	arm   *ast.MatchArm
+}
+
+// compileMatch compiles a match to a Go switch. Matches on union variants
+// become type switches, matches on literals and enum members value switches,
+// and other matches, e.g. on tuples, a switch over the conditions of each arm.
+// When ret is set each arm returns its value, and matches without a catch-all
+// arm panic if no arm matched so that the function still terminates.
+func (c *Go) compileMatch(node *ast.Match, ret bool) {
+	for _, arm := range node.Arms {
+		arm.Pattern = c.resolveBindings(arm.Pattern)
+	}
+
+	var exhaustive bool
+	switch c.matchKind(node) {
+	case typeSwitch:
+		exhaustive = c.compileTypeSwitch(node, ret)
+	case valueSwitch:
+		exhaustive = c.compileValueSwitch(node, ret)
+	default:
+		exhaustive = c.compileCondSwitch(node, ret)
+	}
+
+	if ret && !exhaustive {
+		c.emit("\n")
+		c.emitIndent()
+		c.emit(`panic("unreachable")`)
+	}
+}
+
+// compileMatchValue compiles a match used as a value, e.g. in let x = match,
+// to a function literal returning the value of the matching arm, as Go has
+// no switch expressions. The type returned is typ, or the type of an arm
+// whose value has an evident type, e.g. a literal or enum member.
+func (c *Go) compileMatchValue(node *ast.Match, typ ast.Type) {
+	if typ == nil {
+		typ = c.matchType(node)
+	}
+
+	c.emit("func() ")
+	c.compileType(typ)
+	c.emit(" {\n")
+	c.indent()
+	c.emitIndent()
+	c.compileMatch(node, true)
+	c.outdent()
+	c.emit("\n")
+	c.emitIndent()
+	c.emit("}()")
+}
+
+// matchType returns the type of the first arm of a match whose value has a
+// type evident from the value alone, or nil if there is none. The checker
+// reports matches used as values whose type is not evident.
+func (c *Go) matchType(node *ast.Match) ast.Type {
+	for _, arm := range node.Arms {
+		value := arm.Value
+		if arm.Body != nil && len(arm.Body.Statements) > 0 {
+			if stmt, ok := arm.Body.Statements[len(arm.Body.Statements)-1].(*ast.ExpressionStatement); ok {
+				value = stmt.Expression
+			}
+		}
+
+		switch v := value.(type) {
+		case *ast.Match:
+			if t := c.matchType(v); t != nil {
+				return t
+			}
+		case *ast.IntegerLiteral:
+			return &ast.TypeLiteral{Type: "int"}
+		case *ast.FloatLiteral:
+			return &ast.TypeLiteral{Type: "float"}
+		case *ast.StringLiteral, *ast.TemplateLiteral:
+			return &ast.TypeLiteral{Type: "string"}
+		case *ast.CharLiteral:
+			return &ast.TypeLiteral{Type: "char"}
+		case *ast.Boolean:
+			return &ast.TypeLiteral{Type: "bool"}
+		case *ast.TypeConversion:
+			return v.Type
+		case *ast.MemberExpression:
+			if obj, ok := v.Object.(*ast.Identifier); ok && c.enumMember(obj.Name, v.Property.Name) != nil {
+				return &ast.TypeIdentifier{Name: obj.Name}
+			}
+			if u, f := c.variantOf(v); f != nil && len(u.Parameters) == 0 {
+				return &ast.TypeIdentifier{Name: u.Name}
+			}
+		case *ast.CallExpression:
+			if m, ok := v.Function.(*ast.MemberExpression); ok {
+				if u, f := c.variantOf(m); f != nil && len(u.Parameters) == 0 {
+					return &ast.TypeIdentifier{Name: u.Name}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+type matchKind int
+
+const (
+	condSwitch matchKind = iota
+	valueSwitch
+	typeSwitch
+)
+
+func (c *Go) matchKind(node *ast.Match) matchKind {
+	if _, ok := node.Subject.(*ast.TupleExpression); ok {
+		return condSwitch
+	}
+
+	var unions, values bool
+	for _, arm := range node.Arms {
+		switch p := arm.Pattern.(type) {
+		case *ast.WildcardPattern, *ast.BindingPattern:
+		case *ast.LiteralPattern:
+			values = true
+		case *ast.VariantPattern:
+			if _, f := c.variant(p.Type, p.Name); f != nil {
+				unions = true
+			} else if c.enumMember(p.Type, p.Name) != nil && p.Payload == nil {
+				values = true
+			} else {
+				return condSwitch
+			}
+		default:
+			return condSwitch
+		}
+	}
+
+	switch {
+	case unions && values:
+		return condSwitch
+	case unions:
+		return typeSwitch
+	default:
+		return valueSwitch
+	}
+}
+
+// compileValueSwitch compiles a match on literals and enum members. Arms
+// repeating an earlier case are unreachable and left out, as Go rejects
+// duplicate cases.
+func (c *Go) compileValueSwitch(node *ast.Match, ret bool) bool {
+	var cases []matchCase
+	for _, arm := range node.Arms {
+		conds, binds := c.patternTest(arm, arm.Pattern, matchVar, nil)
+		cases = append(cases, matchCase{conds: conds, binds: binds, arm: arm})
+		if len(conds) == 0 {
+			break
+		}
+	}
+
+	if usesSubject(cases, func(mc matchCase) bool { return len(mc.binds) > 0 }) {
+		c.emit("switch %s := ", matchVar)
+		c.compileExpression(node.Subject)
+		c.emit("; %s {", matchVar)
+	} else {
+		c.emit("switch ")
+		c.compileExpression(node.Subject)
+		c.emit(" {")
+	}
+
+	seen := map[string]bool{}
+	exhaustive := false
+	for _, mc := range cases {
+		label := "default"
+		if len(mc.conds) > 0 {
+			// The condition is "match == value", only the value is needed
+			label = "case " + strings.TrimPrefix(mc.conds[0], matchVar+" == ")
+		}
+		if seen[label] {
+			continue
+		}
+		seen[label] = true
+		exhaustive = exhaustive || len(mc.conds) == 0
+
+		c.emit("\n")
+		c.emitIndent()
+		c.emit("%s:", label)
+		c.compileMatchArm(mc, ret)
+	}
+
+	c.emit("\n")
+	c.emitIndent()
+	c.emit("}")
+	return exhaustive
+}
+
+// compileTypeSwitch compiles a match on union variants. Arms are grouped by
+// variant, and variants matched with refutable payload patterns, e.g.
+// Some(1), switch again on the payload.
+func (c *Go) compileTypeSwitch(node *ast.Match, ret bool) bool {
+	type group struct {
+		name  string
+		cases []matchCase
+	}
+
+	var groups []*group
+	var fallback *matchCase
+	byName := map[string]*group{}
+
+	for _, arm := range node.Arms {
+		p, ok := arm.Pattern.(*ast.VariantPattern)
+		if !ok {
+			_, binds := c.patternTest(arm, arm.Pattern, matchVar, nil)
+			fallback = &matchCase{binds: binds, arm: arm}
+			break
+		}
+
+		u, f := c.variant(p.Type, p.Name)
+		name := variantName(u, f)
+		g := byName[name]
+		if g == nil {
+			g = &group{name: name}
+			byName[name] = g
+			groups = append(groups, g)
+		}
+
+		conds, binds := c.payloadTest(arm, p.Payload, f, matchVar)
+		g.cases = append(g.cases, matchCase{conds: conds, binds: binds, arm: arm})
+	}
+
+	used := fallback != nil && len(fallback.binds) > 0
+	for _, g := range groups {
+		used = used || usesSubject(g.cases, func(mc matchCase) bool { return len(mc.conds)+len(mc.binds) > 0 })
+	}
+
+	if used {
+		c.emit("switch %s := ", matchVar)
+		c.compileExpression(node.Subject)
+		c.emit(".(type) {")
+	} else {
+		c.emit("switch ")
+		c.compileExpression(node.Subject)
+		c.emit(".(type) {")
+	}
+
+	for _, g := range groups {
+		c.emit("\n")
+		c.emitIndent()
+		c.emit("case %s:", g.name)
+
+		if first := g.cases[0]; len(first.conds) == 0 {
+			c.compileMatchArm(first, ret)
+			continue
+		}
+
+		cases := g.cases
+		if fallback != nil {
+			cases = append(cases, *fallback)
+		}
+		c.indent()
+		c.emit("\n")
+		c.emitIndent()
+		c.emit("switch {")
+		c.compileMatchCases(cases, ret)
+		c.emit("\n")
+		c.emitIndent()
+		c.emit("}")
+		c.outdent()
+	}
+
+	if fallback != nil {
+		c.emit("\n")
+		c.emitIndent()
+		c.emit("default:")
+		c.compileMatchArm(*fallback, ret)
+	}
+
+	c.emit("\n")
+	c.emitIndent()
+	c.emit("}")
+	return fallback != nil
+}
+
+// compileCondSwitch compiles a match to a switch over the conditions of each
+// arm. Tuple subjects bind each of their items.
+func (c *Go) compileCondSwitch(node *ast.Match, ret bool) bool {
+	subject := matchVar
+	var items []string
+	tuple, isTuple := node.Subject.(*ast.TupleExpression)
+	if isTuple {
+		subject = ""
+		for i := range tuple.Items {
+			items = append(items, fmt.Sprintf("%s%d", matchVar, i))
+		}
+	}
+
+	var cases []matchCase
+	for _, arm := range node.Arms {
+		conds, binds := c.patternTest(arm, arm.Pattern, subject, items)
+		cases = append(cases, matchCase{conds: conds, binds: binds, arm: arm})
+	}
+
+	switch {
+	case isTuple:
+		// Items which no pattern inspects are not bound, as Go rejects unused
+		// variables
+		names := make([]string, len(items))
+		bound := false
+		for i, item := range items {
+			names[i] = "_"
+			for _, mc := range cases {
+				if t, ok := mc.arm.Pattern.(*ast.TuplePattern); ok && i < len(t.Items) {
+					if conds, binds := c.patternTest(mc.arm, t.Items[i], item, nil); len(conds)+len(binds) > 0 {
+						names[i] = item
+						bound = true
+						break
+					}
+				}
+			}
+		}
+
+		if bound {
+			c.emit("switch %s := ", strings.Join(names, ", "))
+			for i, item := range tuple.Items {
+				if i > 0 {
+					c.emit(", ")
+				}
+				c.compileExpression(item)
+			}
+			c.emit("; {")
+		} else {
+			c.emit("switch {")
+		}
+	case usesSubject(cases, func(mc matchCase) bool { return len(mc.conds)+len(mc.binds) > 0 }):
+		c.emit("switch %s := ", matchVar)
+		c.compileExpression(node.Subject)
+		c.emit("; {")
+	default:
+		c.emit("switch {")
+	}
+
+	exhaustive := c.compileMatchCases(cases, ret)
+	c.emit("\n")
+	c.emitIndent()
+	c.emit("}")
+	return exhaustive
+}
+
+// compileMatchCases emits a case for each arm up to the first which always
+// matches, which becomes the default case.
+func (c *Go) compileMatchCases(cases []matchCase, ret bool) bool {
+	for _, mc := range cases {
+		c.emit("\n")
+		c.emitIndent()
+		if len(mc.conds) == 0 {
+			c.emit("default:")
+			c.compileMatchArm(mc, ret)
+			return true
+		}
+		c.emit("case %s:", strings.Join(mc.conds, " && "))
+		c.compileMatchArm(mc, ret)
+	}
+	return false
+}
+
+func (c *Go) compileMatchArm(mc matchCase, ret bool) {
+	c.indent()
+	for _, bind := range mc.binds {
+		c.emit("\n")
+		c.emitIndent()
+		c.emit("%s", bind)
+	}
+
+	switch {
+	case mc.arm.Body != nil:
+		c.compileStatements(mc.arm.Body.Statements, ret)
+	case mc.arm.Value != nil:
+		c.emit("\n")
+		c.emitIndent()
+		if m, ok := mc.arm.Value.(*ast.Match); ok {
+			c.compileMatch(m, ret)
+			break
+		}
+		if ret {
+			c.emit("return ")
+		}
+		c.compileExpression(mc.arm.Value)
+	}
+	c.outdent()
+}
+
+// resolveBindings resolves the bare names in a pattern which name an enum
+// member or union variant, e.g. None, to variant patterns. Names of more than
+// one are reported by the checker.
+func (c *Go) resolveBindings(pattern ast.Pattern) ast.Pattern {
+	switch p := pattern.(type) {
+	case *ast.BindingPattern:
+		e := c.enumMember("", p.Name)
+		u, f := c.variant("", p.Name)
+		switch {
+		case e != nil && f == nil:
+			return &ast.VariantPattern{Token: p.Token, Type: e.Name, Name: p.Name}
+		case f != nil && e == nil:
+			return &ast.VariantPattern{Token: p.Token, Type: u.Name, Name: p.Name}
+		}
+	case *ast.TuplePattern:
+		for i, item := range p.Items {
+			p.Items[i] = c.resolveBindings(item)
+		}
+	case *ast.VariantPattern:
+		p.Payload = c.resolveBindings(p.Payload)
+	}
+	return pattern
+}
+
+// patternTest returns the Go conditions under which a pattern of an arm
+// matches the value of expr, and the variables it binds. Variables the arm
+// does not use are not bound, as Go rejects unused variables. Tuple patterns
+// match the items of a tuple subject, which are held in items.
+func (c *Go) patternTest(arm *ast.MatchArm, pat ast.Pattern, expr string, items []string) (conds, binds []string) {
+	switch p := pat.(type) {
+	case *ast.BindingPattern:
+		if expr != "" && (uses(arm.Value, p.Name) || uses(arm.Body, p.Name)) {
+			binds = append(binds, fmt.Sprintf("%s := %s", p.Name, expr))
+		}
+
+	case *ast.LiteralPattern:
+		value := c.capture(func() { c.compileExpression(p.Value) })
+		conds = append(conds, fmt.Sprintf("%s == %s", expr, value))
+
+	case *ast.TuplePattern:
+		if len(p.Items) != len(items) {
+			return []string{"false"}, nil
+		}
+		for i, item := range p.Items {
+			itemConds, itemBinds := c.patternTest(arm, item, items[i], nil)
+			conds = append(conds, itemConds...)
+			binds = append(binds, itemBinds...)
+		}
+
+	case *ast.VariantPattern:
+		if e := c.enumMember(p.Type, p.Name); e != nil {
+			conds = append(conds, fmt.Sprintf("%s == %s", expr, e.Name+p.Name))
+			break
+		}
+
+		u, f := c.variant(p.Type, p.Name)
+		if f == nil {
+			return []string{"false"}, nil
+		}
+
+		name := variantName(u, f)
+		conds = append(conds, fmt.Sprintf("func() bool { _, ok := %s.(%s); return ok }()", expr, name))
+		payloadConds, payloadBinds := c.payloadTest(arm, p.Payload, f, fmt.Sprintf("%s.(%s)", expr, name))
+		conds = append(conds, payloadConds...)
+		binds = append(binds, payloadBinds...)
+	}
+	return conds, binds
+}
+
+// payloadTest tests the payload of a variant held in expr. The fields of
+// struct payloads can be matched with a tuple, e.g. Rect(w, h).
+func (c *Go) payloadTest(arm *ast.MatchArm, pat ast.Pattern, f *ast.UnionField, expr string) ([]string, []string) {
+	body, ok := f.Type.(*ast.StructBody)
+	if !ok {
+		return c.patternTest(arm, pat, expr+".Value", nil)
+	}
+
+	if t, ok := pat.(*ast.TuplePattern); ok {
+		fields := make([]string, len(body.Fields))
+		for i, field := range body.Fields {
+			fields[i] = expr + "." + field.Name
+		}
+		return c.patternTest(arm, t, "", fields)
+	}
+	return c.patternTest(arm, pat, expr, nil)
+}
+
+func usesSubject(cases []matchCase, uses func(matchCase) bool) bool {
+	for _, mc := range cases {
+		if uses(mc) {
+			return true
+		}
+	}
+	return false
+}
+
+// uses reports whether a node refers to the variable name. Variables of the
+// same name declared within the node are not distinguished from it.
+func uses(node any, name string) bool {
+	return usesValue(reflect.ValueOf(node), name)
+}
+
+func usesValue(v reflect.Value, name string) bool {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return false
+		}
+		if id, ok := v.Interface().(*ast.Identifier); ok {
+			return id.Name == name
+		}
+		if m, ok := v.Interface().(*ast.MemberExpression); ok {
+			// The property of a member is not a variable
+			return uses(m.Object, name)
+		}
+		return usesValue(v.Elem(), name)
+	case reflect.Slice:
+		for i := range v.Len() {
+			if usesValue(v.Index(i), name) {
+				return true
+			}
+		}
+	case reflect.Struct:
+		for i := range v.NumField() {
+			if usesValue(v.Field(i), name) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,69 @@
+package diagnostic
+
+import (
+	"bytes"
+	"encoding/json"
+	"gloss/token"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestWriteJSON(t *testing.T) {
+	var dl MessageList
+	dl.Error(token.Token{Line: 0, Column: 4}, "Expected name")
+	dl.Warn(token.Token{Line: 2, Column: 0}, "Unused variable")
+
+	var w bytes.Buffer
+	if err := dl.WriteJSON(&w, "main.gloss"); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"file":"main.gloss","line":1,"column":5,"severity":"error","message":"Expected name"}
+{"file":"main.gloss","line":3,"column":1,"severity":"warn","message":"Unused variable"}
+`
+	if diff := cmp.Diff(want, w.String()); diff != "" {
+		t.Errorf("WriteJSON() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestWriteSARIF(t *testing.T) {
+	var dl MessageList
+	dl.Error(token.Token{Line: 1, Column: 2}, "Expected '}'")
+	dl.Raise(token.Token{Line: 0, Column: 0}, "Consider a match")
+
+	var w bytes.Buffer
+	if err := dl.WriteSARIF(&w, "main.gloss"); err != nil {
+		t.Fatal(err)
+	}
+
+	var got sarifLog
+	if err := json.Unmarshal(w.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+
+	location := func(line, col int) []sarifLocation {
+		return []sarifLocation{{
+			PhysicalLocation: sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{URI: "main.gloss"},
+				Region:           sarifRegion{StartLine: line, StartColumn: col},
+			},
+		}}
+	}
+
+	want := sarifLog{
+		Version: "2.1.0",
+		Schema:  sarifSchema,
+		Runs: []sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{Name: "gloss"}},
+			Results: []sarifResult{
+				{Level: "error", Message: sarifMessage{Text: "Expected '}'"}, Locations: location(2, 3)},
+				{Level: "note", Message: sarifMessage{Text: "Consider a match"}, Locations: location(1, 1)},
+			},
+		}},
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("WriteSARIF() mismatch (-want +got):\n%s", diff)
+	}
+}
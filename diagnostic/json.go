@@ -0,0 +1,36 @@
+package diagnostic
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+)
+
+// jsonMessage is the wire format of a single diagnostic. Lines and columns
+// are 1-based to match what editors and CI tooling expect.
+type jsonMessage struct {
+	File     string `json:"file,omitempty"`
+	Line     int    `json:"line"`
+	Column   int    `json:"column"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// WriteJSON writes every message as one JSON object per line (JSON Lines).
+// file is recorded on each message and may be empty.
+func (dl *MessageList) WriteJSON(w io.Writer, file string) error {
+	enc := json.NewEncoder(w)
+	for _, m := range dl.list {
+		err := enc.Encode(jsonMessage{
+			File:     file,
+			Line:     m.Line + 1,
+			Column:   m.Column + 1,
+			Severity: strings.ToLower(m.Severiry.String()),
+			Message:  m.Text,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
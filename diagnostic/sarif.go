@@ -0,0 +1,100 @@
+package diagnostic
+
+import (
+	"encoding/json"
+	"io"
+)
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+)
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name string `json:"name"`
+}
+
+type sarifResult struct {
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn"`
+}
+
+var sarifLevel = map[Severity]string{
+	SeverityError: "error",
+	SeverityWarn:  "warning",
+	SeverityInfo:  "note",
+}
+
+// WriteSARIF writes the messages as a SARIF 2.1.0 log with a single run,
+// suitable for uploading to code scanning dashboards. file is used as the
+// artifact URI of every result.
+func (dl *MessageList) WriteSARIF(w io.Writer, file string) error {
+	run := sarifRun{
+		Tool:    sarifTool{Driver: sarifDriver{Name: "gloss"}},
+		Results: []sarifResult{},
+	}
+
+	for _, m := range dl.list {
+		run.Results = append(run.Results, sarifResult{
+			Level:   sarifLevel[m.Severiry],
+			Message: sarifMessage{Text: m.Text},
+			Locations: []sarifLocation{
+				{
+					PhysicalLocation: sarifPhysicalLocation{
+						ArtifactLocation: sarifArtifactLocation{URI: file},
+						Region: sarifRegion{
+							StartLine:   m.Line + 1,
+							StartColumn: m.Column + 1,
+						},
+					},
+				},
+			},
+		})
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(sarifLog{
+		Version: sarifVersion,
+		Schema:  sarifSchema,
+		Runs:    []sarifRun{run},
+	})
+}
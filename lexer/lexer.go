@@ -1,137 +1,278 @@
 package lexer
 
 import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"gloss/diagnostic"
 	"gloss/token"
+	"io"
+	"iter"
+	"slices"
+	"strings"
 	"unicode"
+	"unicode/utf8"
 )
 
-var keywords = map[string]token.TokenType{
-	"if":       token.IF,
-	"else":     token.ELSE,
-	"for":      token.FOR,
-	"loop":     token.LOOP,
-	"break":    token.BREAK,
-	"continue": token.CONTINUE,
-	"switch":   token.SWITCH,
-	"case":     token.CASE,
-	"default":  token.DEFAULT,
-	"return":   token.RETURN,
-	"let":      token.LET,
-	"fn":       token.FUNC,
-	"enum":     token.ENUM,
-	"union":    token.UNION,
-	"struct":   token.STRUCT,
-	"true":     token.BOOL,
-	"false":    token.BOOL,
-}
-
-var builtins = map[string]token.TokenType{
-	"bool":   token.TYPE_BOOL,
-	"string": token.TYPE_STRING,
-	"int":    token.TYPE_INT,
-}
-
 type Lexer struct {
-	input []byte
+	reader *bufio.Reader
+	err    error // First read error other than io.EOF
 
 	// Current read positions
-	pos  int
-	line int
-	col  int
-	char rune
-
-	braceDepth    int // Tracks nested expressions when inside elements
-	tagBraceDepth int
-	elementDepth  int  // Tracks nested elements
-	insideOpenTag bool // Tracks if we are lexing an element tag which has not yet been terminated by > or />
+	pos   int // Byte offset of char
+	line  int
+	col   int
+	char  rune
+	width int  // Byte width of char
+	eof   bool // True once the input has been fully consumed
+
+	// Bytes consumed since the current token was started, beginning at the
+	// byte offset textStart. Token literals are sliced from here so that the
+	// input never has to be held in memory as a whole.
+	text      []byte
+	textStart int
+
+	// Stack of lexing modes, innermost last. An empty stack is code.
+	modes []frame
 
 	tokenBuffer []token.Token
 	lastToken   *token.Token
+	trivia      []token.Token // Comments waiting to be attached to the next token
+	finished    bool          // Set once EOF has been reached and reported
+
+	Diagnostics *diagnostic.MessageList
+}
+
+// mode determines how the lexer interprets the input. The same characters
+// mean different things in code, inside a tag, as element text and as
+// template text.
+type mode int
+
+const (
+	modeCode     mode = iota // Code, entered at the top level and by '{' or '${'
+	modeTag                  // Attributes of an opening tag, e.g. <div class="a"
+	modeContent              // Children of an element, up to its closing tag
+	modeTemplate             // Text of a template string
+	modeBody                 // Children in the body of an if or for within element content
+)
+
+type frame struct {
+	mode mode
+	open token.Token // The token which entered the mode, used in diagnostics
+
+	// Set on a child expression of an element once it reads an if, else or
+	// for, so that the next '{' starts a body of element children, e.g.
+	// <p>{if ok { Saved }}</p>
+	expectBody bool
 }
 
 func New(input []byte) *Lexer {
+	return NewReader(bytes.NewReader(input))
+}
+
+// NewReader creates a lexer which reads its input incrementally from r.
+func NewReader(r io.Reader) *Lexer {
 	lex := &Lexer{
-		input: input,
-		char:  rune(input[0]),
+		reader:      bufio.NewReader(r),
+		Diagnostics: &diagnostic.MessageList{},
 	}
+	lex.decode()
+	return lex
+}
 
-	if len(input) > 0 {
-		lex.char = rune(input[0])
-	} else {
-		lex.char = 0
+// Err returns the first error encountered while reading the input.
+func (l *Lexer) Err() error {
+	return l.err
+}
+
+// All returns an iterator over the remaining tokens. The final token yielded
+// is always EOF.
+func (l *Lexer) All() iter.Seq[token.Token] {
+	return func(yield func(token.Token) bool) {
+		for {
+			t := l.NextToken()
+			if !yield(t) || t.Type == token.EOF {
+				return
+			}
+		}
 	}
+}
 
-	return lex
+// Tokens reads all remaining tokens, including the final EOF.
+func (l *Lexer) Tokens() []token.Token {
+	return slices.Collect(l.All())
+}
+
+// peekBytes returns the unconsumed bytes starting offset bytes after the
+// start of the current character. Enough bytes are returned to decode one
+// character, fewer near the end of the input.
+func (l *Lexer) peekBytes(offset int) []byte {
+	b, err := l.reader.Peek(offset + 1)
+	if err != nil || b[offset] < utf8.RuneSelf {
+		return b[min(offset, len(b)):]
+	}
+	b, _ = l.reader.Peek(offset + utf8.UTFMax)
+	return b[offset:]
+}
+
+// decode reads the UTF-8 encoded character at the current position.
+func (l *Lexer) decode() {
+	b := l.peekBytes(0)
+	if len(b) == 0 {
+		if _, err := l.reader.Peek(1); err != nil && err != io.EOF && l.err == nil {
+			l.err = err
+		}
+		l.char = 0 // EOF
+		l.width = 0
+		l.eof = true
+		return
+	}
+	l.char, l.width = utf8.DecodeRune(b)
 }
 
+// advance moves to the next character. Columns count code points, not bytes.
 func (l *Lexer) advance() {
-	l.pos++
-	l.col++
-	if l.pos < len(l.input) {
-		l.char = rune(l.input[l.pos])
+	if l.eof {
+		return
+	}
+
+	if l.char == '\n' {
+		l.line++
+		l.col = 0
 	} else {
-		l.char = 0 // EOF
+		l.col++
 	}
+
+	b, _ := l.reader.Peek(l.width)
+	l.text = append(l.text, b...)
+	l.reader.Discard(l.width)
+
+	l.pos += l.width
+	l.decode()
 }
 
 func (l *Lexer) peek() (rune, bool) {
-	if l.pos+1 < len(l.input) {
-		return rune(l.input[l.pos+1]), true
+	if l.eof {
+		return 0, false
+	}
+	b := l.peekBytes(l.width)
+	if len(b) == 0 {
+		return 0, false
+	}
+	r, _ := utf8.DecodeRune(b)
+	return r, true
+}
+
+// hasPrefix reports whether the input at the current position starts with s.
+func (l *Lexer) hasPrefix(s string) bool {
+	b, _ := l.reader.Peek(len(s))
+	return string(b) == s
+}
+
+// compoundAssign returns the assignment form of the operator at the current
+// character when it is followed by '=', e.g. +=, or else the operator.
+func (l *Lexer) compoundAssign(op, assign token.TokenType) (token.TokenType, string) {
+	if next, ok := l.peek(); ok && next == '=' {
+		tl := string(l.char) + "="
+		l.advance()
+		return assign, tl
 	}
-	return 0, false
+	return op, string(l.char)
+}
+
+// literal returns the source text from the byte offset start to the current
+// position. start must not precede the beginning of the current token.
+func (l *Lexer) literal(start int) string {
+	return string(l.text[start-l.textStart : l.pos-l.textStart])
 }
 
 func (l *Lexer) skipWhitespace() {
-	for l.pos < len(l.input) {
-		char := l.input[l.col]
-		if unicode.IsSpace(rune(char)) {
-			if char == '\n' {
-				l.line++
-				l.col = 0
-			}
-			l.advance()
-		} else {
-			break
-		}
+	for !l.eof && unicode.IsSpace(l.char) {
+		l.advance()
 	}
 }
 
-func (l *Lexer) readDigits() token.Token {
+func (l *Lexer) readNumber() token.Token {
 	startCol := l.col
 	startPos := l.pos
 	startRow := l.line
 
-	for l.pos < len(l.input) {
-		if unicode.IsDigit(l.char) || l.char == '_' {
+	tt := token.INT
+
+	if next, ok := l.peek(); ok && l.char == '0' && isBasePrefix(next) {
+		// Alternate bases (0x, 0o, 0b). Any trailing letters are consumed so
+		// that invalid digits are reported against the whole literal.
+		l.advance()
+		l.advance()
+		for !l.eof && (isDigit(l.char) || isLetter(l.char) || l.char == '_') {
 			l.advance()
-		} else {
-			break
+		}
+	} else {
+		l.readDigits()
+
+		// Fraction, only when followed by a digit so that "1.foo" and "0..10"
+		// are not swallowed.
+		if next, ok := l.peek(); ok && l.char == '.' && isDigit(next) {
+			tt = token.FLOAT
+			l.advance()
+			l.readDigits()
+		}
+
+		// Exponent
+		if next, ok := l.peek(); ok && (l.char == 'e' || l.char == 'E') && (isDigit(next) || next == '+' || next == '-') {
+			tt = token.FLOAT
+			l.advance()
+			if l.char == '+' || l.char == '-' {
+				l.advance()
+			}
+			l.readDigits()
 		}
 	}
 
 	return token.Token{
-		Type:    token.INT,
-		Literal: string(l.input[startPos:l.pos]),
+		Type:    tt,
+		Literal: l.literal(startPos),
 		Line:    startRow,
 		Column:  startCol,
 	}
 }
 
+func (l *Lexer) readDigits() {
+	for !l.eof && (isDigit(l.char) || l.char == '_') {
+		l.advance()
+	}
+}
+
 func (l *Lexer) readString() token.Token {
+	return l.readQuoted('"', token.STRING)
+}
+
+func (l *Lexer) readChar() token.Token {
+	return l.readQuoted('\'', token.CHAR)
+}
+
+// readQuoted reads a literal delimited by quote, skipping over escapes. The
+// literal keeps its quotes and escapes, decoding is left to the parser.
+func (l *Lexer) readQuoted(quote rune, tt token.TokenType) token.Token {
 	start := l.pos
 	startCol := l.col
 	startLine := l.line
 
-	// Eat opening "
+	// Eat opening quote
 	l.advance()
 
 	for {
-		if l.char == '"' {
+		if l.char == quote {
 			break
 		}
 
-		if l.char == 0 {
-			// Error: Reached EOF without closing quote
+		if l.eof {
+			start := token.Token{Line: startLine, Column: startCol}
+			if quote == '"' {
+				l.Diagnostics.Error(start, "Unterminated string literal")
+			} else {
+				l.Diagnostics.Error(start, "Unterminated char literal")
+			}
 			break
 		}
 
@@ -139,9 +280,9 @@ func (l *Lexer) readString() token.Token {
 		if l.char == '\\' {
 			l.advance() // Skip the backslash
 
-			if l.char != 0 {
+			if !l.eof {
 				// Skip the next character (the escaped char), preventing
-				// the loop from breaking if that char happens to be '"'
+				// the loop from breaking if that char happens to be the quote
 				l.advance()
 			}
 			continue
@@ -150,12 +291,35 @@ func (l *Lexer) readString() token.Token {
 		l.advance()
 	}
 
-	// Eat closing "
+	// Eat closing quote
 	l.advance()
 
 	return token.Token{
-		Type:    token.STRING,
-		Literal: string(l.input[start:l.pos]),
+		Type:    tt,
+		Literal: l.literal(start),
+		Line:    startLine,
+		Column:  startCol,
+	}
+}
+
+// readTemplateText reads the raw text of a template string up to the closing
+// backtick or the start of an interpolation.
+func (l *Lexer) readTemplateText() token.Token {
+	startPos := l.pos
+	startCol := l.col
+	startLine := l.line
+
+	for !l.eof && l.char != '`' {
+		if next, ok := l.peek(); ok && l.char == '$' && next == '{' {
+			break
+		}
+
+		l.advance()
+	}
+
+	return token.Token{
+		Type:    token.TEMPLATE_TEXT,
+		Literal: l.literal(startPos),
 		Line:    startLine,
 		Column:  startCol,
 	}
@@ -166,7 +330,7 @@ func (l *Lexer) readElementIdentifier() token.Token {
 	startPos := l.pos
 	startRow := l.line
 
-	for l.pos < len(l.input) {
+	for !l.eof {
 		if unicode.IsLetter(l.char) || unicode.IsDigit(l.char) || l.char == '.' {
 			l.advance()
 		} else {
@@ -176,7 +340,7 @@ func (l *Lexer) readElementIdentifier() token.Token {
 
 	return token.Token{
 		Type:    token.ELEMENT_IDENT,
-		Literal: string(l.input[startPos:l.pos]),
+		Literal: l.literal(startPos),
 		Line:    startRow,
 		Column:  startCol,
 	}
@@ -187,19 +351,14 @@ func (l *Lexer) readElementText() token.Token {
 	startCol := l.col
 	startLine := l.line
 
-	// Consume until we hit the start of a tag '<' or an expression '{'
-	for l.pos < len(l.input) {
-		if l.char == '<' || l.char == '{' {
+	// Consume until we hit the start of a tag or an expression '{', or the
+	// end of a body. Any other '<' is text, e.g. <p>a < b</p>
+	for !l.eof {
+		if l.char == '{' || (l.char == '}' && l.mode() == modeBody) {
 			break
 		}
-
-		// Handle newlines within text
-		// TODO: Move this to l.advance()
-		if l.char == '\n' {
-			l.line++
-			l.col = 0
-			l.advance()
-			continue
+		if next, _ := l.peek(); l.char == '<' && (next == '/' || startsTag(next)) {
+			break
 		}
 
 		l.advance()
@@ -207,7 +366,7 @@ func (l *Lexer) readElementText() token.Token {
 
 	return token.Token{
 		Type:    token.ELEMENT_TEXT,
-		Literal: string(l.input[startPos:l.pos]),
+		Literal: l.literal(startPos),
 		Line:    startLine,
 		Column:  startCol,
 	}
@@ -217,13 +376,13 @@ func (l *Lexer) readAttributeName() token.Token {
 	start := l.pos
 	startCol := l.col
 
-	for l.pos < len(l.input) && (unicode.IsLetter(l.char) || unicode.IsDigit(l.char) || l.char == '-') {
+	for !l.eof && (unicode.IsLetter(l.char) || unicode.IsDigit(l.char) || l.char == '-') {
 		l.advance()
 	}
 
 	return token.Token{
 		Type:    token.ELEMENT_ATTR,
-		Literal: string(l.input[start:l.pos]),
+		Literal: l.literal(start),
 		Line:    l.line,
 		Column:  startCol,
 	}
@@ -233,18 +392,70 @@ func (l *Lexer) readIdentifer() token.Token {
 	start := l.pos
 	startCol := l.col
 
-	for l.pos < len(l.input) && (isLetter(l.char) || isDigit(l.char) || l.char == '_') {
+	for !l.eof && (isLetter(l.char) || unicode.IsDigit(l.char) || l.char == '_') {
 		l.advance()
 	}
 
 	return token.Token{
 		Type:    token.IDENT,
-		Literal: string(l.input[start:l.pos]),
+		Literal: l.literal(start),
 		Line:    l.line,
 		Column:  startCol,
 	}
 }
 
+func (l *Lexer) mode() mode {
+	if len(l.modes) == 0 {
+		return modeCode
+	}
+	return l.modes[len(l.modes)-1].mode
+}
+
+func (l *Lexer) push(m mode, open token.Token) {
+	l.modes = append(l.modes, frame{mode: m, open: open})
+}
+
+// markBody records that the next '{' of an element's child expression starts
+// the body of a control flow expression.
+func (l *Lexer) markBody(tt token.TokenType) {
+	n := len(l.modes)
+	if n < 2 || l.modes[n-1].mode != modeCode {
+		return
+	}
+	if parent := l.modes[n-2].mode; parent != modeContent && parent != modeBody {
+		return
+	}
+
+	switch tt {
+	case token.IF, token.ELSE, token.FOR:
+		l.modes[n-1].expectBody = true
+	}
+}
+
+func (l *Lexer) pop() {
+	if len(l.modes) > 0 {
+		l.modes = l.modes[:len(l.modes)-1]
+	}
+}
+
+// expectsOperand reports whether the last token leaves the lexer at a point
+// where an operand may start. Only there does a '<' open an element; after an
+// operand it is a comparison or the start of type parameters, e.g. a <b or
+// Option<T>. As statements are not terminated, an operand on a previous line
+// ends its statement.
+func (l *Lexer) expectsOperand() bool {
+	if l.lastToken == nil || l.lastToken.Line < l.line {
+		return true
+	}
+
+	switch l.lastToken.Type {
+	case token.IDENT, token.INT, token.FLOAT, token.STRING, token.CHAR, token.BOOL,
+		token.RPAREN, token.RBRACKET, token.BACKTICK:
+		return false
+	}
+	return !l.lastToken.Type.IsBuiltinType()
+}
+
 func (l *Lexer) readTagStart() []token.Token {
 	tokens := []token.Token{
 		{Type: token.ELEMENT_OPEN_START, Literal: "<", Line: l.line, Column: l.col},
@@ -252,13 +463,20 @@ func (l *Lexer) readTagStart() []token.Token {
 
 	l.advance() // Eat '<'
 
+	// Fragments have no name or attributes, e.g. <>...</>
+	if l.char == '>' {
+		l.push(modeContent, token.Token{Type: token.ELEMENT_IDENT, Line: tokens[0].Line, Column: tokens[0].Column})
+		tokens = append(tokens, token.Token{Type: token.ELEMENT_OPEN_END, Literal: ">", Line: l.line, Column: l.col})
+		l.advance()
+		return tokens
+	}
+
 	// Read Tag Name
-	tokens = append(tokens, l.readElementIdentifier())
+	name := l.readElementIdentifier()
+	tokens = append(tokens, name)
 
 	// Update State
-	l.elementDepth++
-	l.tagBraceDepth = l.braceDepth
-	l.insideOpenTag = true
+	l.push(modeTag, name)
 
 	return tokens
 }
@@ -276,11 +494,6 @@ func (l *Lexer) tryReadTagEnd() ([]token.Token, bool) {
 	l.advance()
 	l.skipWhitespace()
 
-	// 2. Read Element Name
-	if !unicode.IsLetter(l.char) {
-		return nil, false
-	}
-
 	tokens := []token.Token{
 		{
 			Type:    token.ELEMENT_CLOSE_START,
@@ -288,13 +501,26 @@ func (l *Lexer) tryReadTagEnd() ([]token.Token, bool) {
 			Line:    tagStartRow,
 			Column:  tagStartCol,
 		},
-		l.readElementIdentifier(),
 	}
 
+	// 2. Read Element Name, which fragments (</>) do not have
+	var name token.Token
+	if l.char == '>' {
+		name = token.Token{Type: token.ELEMENT_IDENT, Line: l.line, Column: l.col}
+	} else {
+		if !unicode.IsLetter(l.char) {
+			l.Diagnostics.Error(token.Token{Line: l.line, Column: l.col}, "Expected element name after '</'")
+		}
+		name = l.readElementIdentifier()
+		tokens = append(tokens, name)
+	}
+
+	l.closeElement(name)
 	l.skipWhitespace()
 
 	if l.char != '>' {
-		return nil, false
+		l.Diagnostics.Error(token.Token{Line: l.line, Column: l.col}, fmt.Sprintf("Expected '>' to close </%s", name.Literal))
+		return tokens, true
 	}
 
 	tokens = append(tokens,
@@ -306,13 +532,109 @@ func (l *Lexer) tryReadTagEnd() ([]token.Token, bool) {
 		},
 	)
 
-	l.elementDepth-- // Decrement depth
-	l.advance()      // Eat '>'
+	l.advance() // Eat '>'
 
 	return tokens, true
 }
 
+// closeElement leaves the content of the innermost element, reporting closing
+// tags which do not match it.
+func (l *Lexer) closeElement(name token.Token) {
+	if l.mode() != modeContent {
+		l.Diagnostics.Error(name, fmt.Sprintf("Unexpected closing tag </%s>", name.Literal))
+		return
+	}
+
+	open := l.modes[len(l.modes)-1].open
+	if open.Literal != name.Literal {
+		l.Diagnostics.Error(name, fmt.Sprintf("Mismatched closing tag </%s>, expected </%s>", name.Literal, open.Literal))
+	}
+	l.pop()
+}
+
+// reportUnterminated reports the constructs left open at the end of input.
+func (l *Lexer) reportUnterminated() {
+	for _, f := range l.modes {
+		switch f.mode {
+		case modeTemplate:
+			l.Diagnostics.Error(f.open, "Unterminated template string")
+		case modeTag, modeContent:
+			l.Diagnostics.Error(f.open, fmt.Sprintf("Unclosed element <%s>", f.open.Literal))
+		}
+	}
+}
+
+func (l *Lexer) readComment() token.Token {
+	start := l.pos
+	startCol := l.col
+	startLine := l.line
+
+	// Eat the opening "/"
+	l.advance()
+
+	if l.char == '*' {
+		l.advance()
+		for {
+			if l.eof {
+				l.Diagnostics.Error(token.Token{Line: startLine, Column: startCol}, "Unterminated block comment")
+				break
+			}
+			if l.char == '*' {
+				if next, ok := l.peek(); ok && next == '/' {
+					l.advance()
+					l.advance()
+					break
+				}
+			}
+			l.advance()
+		}
+
+		return token.Token{
+			Type:    token.COMMENT,
+			Literal: l.literal(start),
+			Line:    startLine,
+			Column:  startCol,
+		}
+	}
+
+	for !l.eof && l.char != '\n' {
+		l.advance()
+	}
+
+	literal := l.literal(start)
+	tt := token.COMMENT
+
+	// "///" starts a doc comment, but "////" is just a comment
+	if strings.HasPrefix(literal, "///") && !strings.HasPrefix(literal, "////") {
+		tt = token.DOC_COMMENT
+	}
+
+	return token.Token{
+		Type:    tt,
+		Literal: literal,
+		Line:    startLine,
+		Column:  startCol,
+	}
+}
+
+// NextToken returns the next significant token. Any comments found along the
+// way are attached to it as trivia.
 func (l *Lexer) NextToken() token.Token {
+	t := l.nextToken()
+	for t.Type == token.COMMENT || t.Type == token.DOC_COMMENT {
+		l.trivia = append(l.trivia, t)
+		t = l.nextToken()
+	}
+
+	if len(l.trivia) > 0 {
+		t.Trivia = l.trivia
+		l.trivia = nil
+	}
+
+	return t
+}
+
+func (l *Lexer) nextToken() token.Token {
 	// Drain buffer
 	if len(l.tokenBuffer) > 0 {
 		t := l.tokenBuffer[0]
@@ -321,37 +643,91 @@ func (l *Lexer) NextToken() token.Token {
 		return t
 	}
 
-	for l.pos < len(l.input) {
+	// Literals of previous tokens have been copied, start a fresh buffer
+	l.text = l.text[:0]
+	l.textStart = l.pos
+
+	for !l.eof {
 		startCol := l.col
 
+		switch l.mode() {
 		// ---------------------------------------------------------
-		//  MODE 1: ELEMENT CONTENT
+		//  TEMPLATE STRING TEXT
 		// ---------------------------------------------------------
-		// If we are inside an element, but NOT inside an expression block ({...}),
-		// we treat content as raw text.
-		if l.elementDepth > 0 && l.braceDepth == 0 && !l.insideOpenTag {
-			// If we hit '<', check if it's a valid tag (start or close)
-			// If we hit '{', we switch to Code Mode (handled below in standard switch)
-			// Otherwise, it is text.
-			if l.char != '<' && l.char != '{' {
-				t := l.readElementText()
+		case modeTemplate:
+			if l.char == '`' {
+				t := token.Token{Type: token.BACKTICK, Literal: "`", Line: l.line, Column: startCol}
+				l.pop()
+				l.advance()
+				l.lastToken = &t
+				return t
+			}
+
+			if next, ok := l.peek(); ok && l.char == '$' && next == '{' {
+				t := token.Token{Type: token.TEMPLATE_EXPR_START, Literal: "${", Line: l.line, Column: startCol}
+				l.push(modeCode, t)
+				l.advance()
+				l.advance()
+				l.lastToken = &t
+				return t
+			}
+
+			t := l.readTemplateText()
+			l.lastToken = &t
+			return t
+
+		// ---------------------------------------------------------
+		//  ELEMENT CONTENT
+		// ---------------------------------------------------------
+		// Children are raw text up to a tag or an expression block ({...}).
+		// Bodies of control flow in children also end at a '}'.
+		case modeContent, modeBody:
+			next, _ := l.peek()
+
+			if l.char == '}' && l.mode() == modeBody {
+				t := token.Token{Type: token.RBRACE, Literal: "}", Line: l.line, Column: startCol}
+				l.pop()
+				l.advance()
 				l.lastToken = &t
 				return t
 			}
-		}
+
+			if l.char == '<' && next == '/' {
+				toks, _ := l.tryReadTagEnd()
+				t := toks[0]
+				l.tokenBuffer = append(l.tokenBuffer, toks[1:]...)
+				l.lastToken = &t
+				return t
+			}
+
+			if l.char == '<' && startsTag(next) {
+				toks := l.readTagStart()
+				t := toks[0]
+				l.tokenBuffer = append(l.tokenBuffer, toks[1:]...)
+				l.lastToken = &t
+				return t
+			}
+
+			if l.char == '{' {
+				t := token.Token{Type: token.LBRACE, Literal: "{", Line: l.line, Column: startCol}
+				l.push(modeCode, t)
+				l.advance()
+				l.lastToken = &t
+				return t
+			}
+
+			t := l.readElementText()
+			l.lastToken = &t
+			return t
 
 		// ----------------------------------------------------------------
-		// MODE 2: ATTRIBUTE SCANNING
+		// ATTRIBUTE SCANNING
 		// Inside an opening tag definition <div ... >
 		// But NOT inside an attribute expression like prop={...}
 		// ----------------------------------------------------------------
-		if l.insideOpenTag && l.braceDepth == l.tagBraceDepth {
+		case modeTag:
 			// 1. Ignore whitespace
 			if unicode.IsSpace(l.char) {
-				if l.char == '\n' {
-					l.line++
-					l.col = 0
-				}
 				l.advance()
 				continue
 			}
@@ -359,7 +735,7 @@ func (l *Lexer) NextToken() token.Token {
 			// 2.a Open Tag Endings
 			if l.char == '>' {
 				t := token.Token{Type: token.ELEMENT_OPEN_END, Literal: ">", Line: l.line, Column: startCol}
-				l.insideOpenTag = false
+				l.modes[len(l.modes)-1].mode = modeContent
 				l.advance()
 				l.lastToken = &t
 				return t
@@ -369,8 +745,7 @@ func (l *Lexer) NextToken() token.Token {
 			if l.char == '/' {
 				if next, ok := l.peek(); ok && next == '>' {
 					t := token.Token{Type: token.ELEMENT_VOID_END, Literal: "/>", Line: l.line, Column: startCol}
-					l.insideOpenTag = false
-					l.elementDepth--
+					l.pop()
 					l.advance()
 					l.advance()
 					l.lastToken = &t
@@ -379,10 +754,10 @@ func (l *Lexer) NextToken() token.Token {
 			}
 
 			// 3. Expressions start
-			// We emit the brace, increment depth, and let the NEXT loop iteration handle the inside as Standard Code.
+			// We emit the brace and let the NEXT loop iteration handle the inside as Standard Code.
 			if l.char == '{' {
 				t := token.Token{Type: token.LBRACE, Literal: "{", Line: l.line, Column: startCol}
-				l.braceDepth++
+				l.push(modeCode, t)
 				l.advance()
 				l.lastToken = &t
 				return t
@@ -417,10 +792,6 @@ func (l *Lexer) NextToken() token.Token {
 
 		// Skip whitespace
 		if unicode.IsSpace(l.char) {
-			if l.char == '\n' {
-				l.line++
-				l.col = 0
-			}
 			l.advance()
 			continue
 		}
@@ -428,28 +799,15 @@ func (l *Lexer) NextToken() token.Token {
 		// Identifiers, Keywords, and Builtins
 		if isLetter(l.char) || l.char == '_' {
 			identToken := l.readIdentifer()
-
-			// Text is a keyword?
-			if tokenType, ok := keywords[identToken.Literal]; ok {
-				identToken.Type = tokenType
-				l.lastToken = &identToken
-				return identToken
-			}
-
-			// Text is a builtin?
-			if tokenType, ok := builtins[identToken.Literal]; ok {
-				identToken.Type = tokenType
-				l.lastToken = &identToken
-				return identToken
-			}
-
+			identToken.Type = token.Lookup(identToken.Literal)
+			l.markBody(identToken.Type)
 			l.lastToken = &identToken
 			return identToken
 		}
 
 		// Numbers
 		if isDigit(l.char) {
-			t := l.readDigits()
+			t := l.readNumber()
 			l.lastToken = &t
 			return t
 		}
@@ -461,34 +819,31 @@ func (l *Lexer) NextToken() token.Token {
 			return t
 		}
 
-		// Check for Tag Start
-
-		// These patterns should not be treated as elements
-		// union Option<T> {}
-		// struct Point<T> { x: T, y: T }
-		// fn join<T>(a: T, b: T) T {}
-
-		if l.char == '<' && !maybeExpectingGenericParameters(l.lastToken) {
+		// Chars
+		if l.char == '\'' {
+			t := l.readChar()
+			l.lastToken = &t
+			return t
+		}
 
-			// Check if it is a closing tag </...
-			next, hasNext := l.peek()
+		// Check for Tag Start
+		// A '<' only opens an element where an operand is expected, so these
+		// are not elements:
+		//	union Option<T> {}
+		//	fn join<T>(a: T, b: T) T {}
+		//	if a <b {}
+		if next, ok := l.peek(); ok && l.char == '<' && startsTag(next) && l.expectsOperand() {
+			toks := l.readTagStart()
+			t := toks[0]
+			l.tokenBuffer = append(l.tokenBuffer, toks[1:]...)
+			l.lastToken = &t
+			return t
+		}
 
-			if hasNext && next == '/' {
-				if toks, ok := l.tryReadTagEnd(); ok {
-					t := toks[0]
-					l.tokenBuffer = append(l.tokenBuffer, toks[1:]...)
-					l.lastToken = &t
-					return t
-				}
-			}
-			// Check if it is an opening tag <div...
-			// Must ensure it's not a Less Than operator (e.g. "if a < b")
-			if hasNext && unicode.IsLetter(next) {
-				toks := l.readTagStart()
-				t := toks[0]
-				l.tokenBuffer = append(l.tokenBuffer, toks[1:]...)
-				l.lastToken = &t
-				return t
+		// Comments
+		if l.char == '/' {
+			if next, ok := l.peek(); ok && (next == '/' || next == '*') {
+				return l.readComment()
 			}
 		}
 
@@ -499,24 +854,44 @@ func (l *Lexer) NextToken() token.Token {
 		switch l.char {
 		case ':':
 			tt = token.COLON
-		case '\'':
-			tt = token.TICK
+		case '?':
+			tt = token.QUESTION
 		case '`':
 			tt = token.BACKTICK
+			l.push(modeTemplate, token.Token{Type: tt, Literal: tl, Line: l.line, Column: startCol})
 		case '.':
-			tt = token.PERIOD
+			switch {
+			case l.hasPrefix("..."):
+				tt = token.ELLIPSIS
+				tl = "..."
+				l.advance()
+				l.advance()
+			case l.hasPrefix("..="):
+				tt = token.RANGE_INCL
+				tl = "..="
+				l.advance()
+				l.advance()
+			case l.hasPrefix(".."):
+				tt = token.RANGE
+				tl = ".."
+				l.advance()
+			default:
+				tt = token.PERIOD
+			}
 		case ',':
 			tt = token.COMMA
+		case ';':
+			tt = token.SEMICOLON
 		case '+':
-			tt = token.PLUS
+			tt, tl = l.compoundAssign(token.PLUS, token.PLUS_ASSIGN)
 		case '-':
-			tt = token.MINUS
+			tt, tl = l.compoundAssign(token.MINUS, token.MINUS_ASSIGN)
 		case '*':
-			tt = token.MUL
+			tt, tl = l.compoundAssign(token.MUL, token.MUL_ASSIGN)
 		case '/':
-			tt = token.DIV
+			tt, tl = l.compoundAssign(token.DIV, token.DIV_ASSIGN)
 		case '%':
-			tt = token.MOD
+			tt, tl = l.compoundAssign(token.MOD, token.MOD_ASSIGN)
 		case '[':
 			tt = token.LBRACKET
 		case ']':
@@ -527,12 +902,17 @@ func (l *Lexer) NextToken() token.Token {
 			tt = token.RPAREN
 		case '{':
 			tt = token.LBRACE
-			l.braceDepth++
+			open := token.Token{Type: tt, Literal: tl, Line: l.line, Column: startCol}
+			if n := len(l.modes); n > 0 && l.modes[n-1].expectBody {
+				l.modes[n-1].expectBody = false
+				l.push(modeBody, open)
+			} else {
+				l.push(modeCode, open)
+			}
 		case '}':
+			// Returns to the enclosing mode, e.g. the template text after ${...}
 			tt = token.RBRACE
-			if l.braceDepth > 0 {
-				l.braceDepth--
-			}
+			l.pop()
 
 		// Possible operators
 		case '^':
@@ -564,6 +944,10 @@ func (l *Lexer) NextToken() token.Token {
 				tt = token.EQ
 				tl = "=="
 				l.advance()
+			} else if next == '>' {
+				tt = token.FAT_ARROW
+				tl = "=>"
+				l.advance()
 			} else {
 				tt = token.ASSIGN
 			}
@@ -604,6 +988,7 @@ func (l *Lexer) NextToken() token.Token {
 			}
 		default:
 			tt = token.ILLEGAL
+			l.Diagnostics.Error(token.Token{Line: l.line, Column: startCol}, fmt.Sprintf("Unexpected character %q", l.char))
 		}
 
 		t := token.Token{Type: tt, Literal: tl, Line: l.line, Column: startCol}
@@ -612,6 +997,11 @@ func (l *Lexer) NextToken() token.Token {
 		return t
 	}
 
+	if !l.finished {
+		l.finished = true
+		l.reportUnterminated()
+	}
+
 	return token.Token{Type: token.EOF, Line: l.line, Column: l.col}
 }
 
@@ -621,16 +1011,20 @@ func isDigit(ch rune) bool {
 	return ch >= '0' && ch <= '9'
 }
 
+func isBasePrefix(ch rune) bool {
+	switch ch {
+	case 'x', 'X', 'o', 'O', 'b', 'B':
+		return true
+	}
+	return false
+}
+
 func isLetter(ch rune) bool {
-	return ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z'
+	return unicode.IsLetter(ch)
 }
 
-func maybeExpectingGenericParameters(tok *token.Token) bool {
-	if tok != nil {
-		switch tok.Type {
-		case token.IDENT:
-			return true
-		}
-	}
-	return false
+// startsTag reports whether the character following a '<' opens an element
+// or a fragment.
+func startsTag(r rune) bool {
+	return unicode.IsLetter(r) || r == '>'
 }
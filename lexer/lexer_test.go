@@ -1,9 +1,14 @@
 package lexer
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
 	"gloss/token"
-
+	"io"
+	"strings"
 	"testing"
+	"testing/iotest"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
@@ -21,7 +26,7 @@ func TestNextToken(t *testing.T) {
 	}{
 		{
 			name:  "Math tokens",
-			input: "+=-*/%.",
+			input: "+ =-*/%.",
 			want: []token.Token{
 				{Type: token.PLUS, Literal: "+"},
 				{Type: token.ASSIGN, Literal: "="},
@@ -33,24 +38,38 @@ func TestNextToken(t *testing.T) {
 				{Type: token.EOF},
 			},
 		},
+		{
+			name:  "Assignment tokens",
+			input: "= += -= *= /= %=",
+			want: []token.Token{
+				{Type: token.ASSIGN, Literal: "="},
+				{Type: token.PLUS_ASSIGN, Literal: "+="},
+				{Type: token.MINUS_ASSIGN, Literal: "-="},
+				{Type: token.MUL_ASSIGN, Literal: "*="},
+				{Type: token.DIV_ASSIGN, Literal: "/="},
+				{Type: token.MOD_ASSIGN, Literal: "%="},
+				{Type: token.EOF},
+			},
+		},
 		{
 			name:  "Bracket tokens",
-			input: "(){}<>[]",
+			input: "()[]<>{}",
 			want: []token.Token{
 				{Type: token.LPAREN, Literal: "("},
 				{Type: token.RPAREN, Literal: ")"},
-				{Type: token.LBRACE, Literal: "{"},
-				{Type: token.RBRACE, Literal: "}"},
-				{Type: token.LANGLE, Literal: "<"},
-				{Type: token.RANGLE, Literal: ">"},
 				{Type: token.LBRACKET, Literal: "["},
 				{Type: token.RBRACKET, Literal: "]"},
+				// '<' after a value is an operator rather than an element
+				{Type: token.LANGLE, Literal: "<"},
+				{Type: token.RANGLE, Literal: ">"},
+				{Type: token.LBRACE, Literal: "{"},
+				{Type: token.RBRACE, Literal: "}"},
 				{Type: token.EOF},
 			},
 		},
 		{
 			name:  "Operators",
-			input: "== != >= <= && ||",
+			input: "== != >= <= && || => .. ..= ;",
 			want: []token.Token{
 				{Type: token.EQ, Literal: "=="},
 				{Type: token.NOT_EQ, Literal: "!="},
@@ -58,27 +77,34 @@ func TestNextToken(t *testing.T) {
 				{Type: token.LT_EQ, Literal: "<="},
 				{Type: token.AND, Literal: "&&"},
 				{Type: token.OR, Literal: "||"},
+				{Type: token.FAT_ARROW, Literal: "=>"},
+				{Type: token.RANGE, Literal: ".."},
+				{Type: token.RANGE_INCL, Literal: "..="},
+				{Type: token.SEMICOLON, Literal: ";"},
 				{Type: token.EOF},
 			},
 		},
 		{
 			name:  "Keyword tokens",
-			input: "enum struct union if else switch case default break continue for fn return let foo # true false",
+			input: "enum struct interface union if else switch case default match break continue for fn return let mut foo # true false",
 			want: []token.Token{
 				{Type: token.ENUM, Literal: "enum"},
 				{Type: token.STRUCT, Literal: "struct"},
+				{Type: token.INTERFACE, Literal: "interface"},
 				{Type: token.UNION, Literal: "union"},
 				{Type: token.IF, Literal: "if"},
 				{Type: token.ELSE, Literal: "else"},
 				{Type: token.SWITCH, Literal: "switch"},
 				{Type: token.CASE, Literal: "case"},
 				{Type: token.DEFAULT, Literal: "default"},
+				{Type: token.MATCH, Literal: "match"},
 				{Type: token.BREAK, Literal: "break"},
 				{Type: token.CONTINUE, Literal: "continue"},
 				{Type: token.FOR, Literal: "for"},
 				{Type: token.FUNC, Literal: "fn"},
 				{Type: token.RETURN, Literal: "return"},
 				{Type: token.LET, Literal: "let"},
+				{Type: token.MUT, Literal: "mut"},
 				{Type: token.IDENT, Literal: "foo"},
 				{Type: token.ILLEGAL, Literal: "#"},
 				{Type: token.BOOL, Literal: "true"},
@@ -89,10 +115,12 @@ func TestNextToken(t *testing.T) {
 
 		{
 			name:  "Builtin tokens",
-			input: "string int bool",
+			input: "string int float char bool",
 			want: []token.Token{
 				{Type: token.TYPE_STRING, Literal: "string"},
 				{Type: token.TYPE_INT, Literal: "int"},
+				{Type: token.TYPE_FLOAT, Literal: "float"},
+				{Type: token.TYPE_CHAR, Literal: "char"},
 				{Type: token.TYPE_BOOL, Literal: "bool"},
 				{Type: token.EOF},
 			},
@@ -109,6 +137,7 @@ func TestNextToken(t *testing.T) {
 			1__0
 			"hello world"
 			"with \"quoted\""
+			'a' '\n' '\''
 			`,
 			want: []token.Token{
 				// Integers
@@ -121,6 +150,128 @@ func TestNextToken(t *testing.T) {
 				// Strings
 				{Type: token.STRING, Literal: `"hello world"`},
 				{Type: token.STRING, Literal: `"with \"quoted\""`},
+				// Chars
+				{Type: token.CHAR, Literal: `'a'`},
+				{Type: token.CHAR, Literal: `'\n'`},
+				{Type: token.CHAR, Literal: `'\''`},
+				{Type: token.EOF},
+			},
+		},
+
+		{
+			name: "Numeric literals",
+			input: `
+			0x1F 0o17 0b1010 0B1_0 0xZZ
+			1.5 0.25 1e10 2.5E-3 1_000.000_1
+			1.foo 0..10
+			`,
+			want: []token.Token{
+				{Type: token.INT, Literal: "0x1F"},
+				{Type: token.INT, Literal: "0o17"},
+				{Type: token.INT, Literal: "0b1010"},
+				{Type: token.INT, Literal: "0B1_0"},
+				{Type: token.INT, Literal: "0xZZ"}, // syntax error
+				{Type: token.FLOAT, Literal: "1.5"},
+				{Type: token.FLOAT, Literal: "0.25"},
+				{Type: token.FLOAT, Literal: "1e10"},
+				{Type: token.FLOAT, Literal: "2.5E-3"},
+				{Type: token.FLOAT, Literal: "1_000.000_1"},
+				{Type: token.INT, Literal: "1"},
+				{Type: token.PERIOD, Literal: "."},
+				{Type: token.IDENT, Literal: "foo"},
+				{Type: token.INT, Literal: "0"},
+				{Type: token.RANGE, Literal: ".."},
+				{Type: token.INT, Literal: "10"},
+				{Type: token.EOF},
+			},
+		},
+
+		{
+			name:  "Template strings",
+			input: "`raw \\n` `a ${b + `c ${d}`} $e {f}`",
+			want: []token.Token{
+				{Type: token.BACKTICK, Literal: "`"},
+				{Type: token.TEMPLATE_TEXT, Literal: "raw \\n"},
+				{Type: token.BACKTICK, Literal: "`"},
+				{Type: token.BACKTICK, Literal: "`"},
+				{Type: token.TEMPLATE_TEXT, Literal: "a "},
+				{Type: token.TEMPLATE_EXPR_START, Literal: "${"},
+				{Type: token.IDENT, Literal: "b"},
+				{Type: token.PLUS, Literal: "+"},
+				{Type: token.BACKTICK, Literal: "`"},
+				{Type: token.TEMPLATE_TEXT, Literal: "c "},
+				{Type: token.TEMPLATE_EXPR_START, Literal: "${"},
+				{Type: token.IDENT, Literal: "d"},
+				{Type: token.RBRACE, Literal: "}"},
+				{Type: token.BACKTICK, Literal: "`"},
+				{Type: token.RBRACE, Literal: "}"},
+				{Type: token.TEMPLATE_TEXT, Literal: " $e {f}"},
+				{Type: token.BACKTICK, Literal: "`"},
+				{Type: token.EOF},
+			},
+		},
+
+		{
+			name:  "Empty input",
+			input: "",
+			want: []token.Token{
+				{Type: token.EOF},
+			},
+		},
+		{
+			name: "Unicode identifiers and text",
+			input: `let café_2 = "naïve"
+<p lang="de">Grüße, 世界 {π}</p> ½`,
+			want: []token.Token{
+				{Type: token.LET, Literal: "let"},
+				{Type: token.IDENT, Literal: "café_2"},
+				{Type: token.ASSIGN, Literal: "="},
+				{Type: token.STRING, Literal: `"naïve"`},
+				{Type: token.ELEMENT_OPEN_START, Literal: "<"},
+				{Type: token.ELEMENT_IDENT, Literal: "p"},
+				{Type: token.ELEMENT_ATTR, Literal: "lang"},
+				{Type: token.ASSIGN, Literal: "="},
+				{Type: token.STRING, Literal: `"de"`},
+				{Type: token.ELEMENT_OPEN_END, Literal: ">"},
+				{Type: token.ELEMENT_TEXT, Literal: "Grüße, 世界 "},
+				{Type: token.LBRACE, Literal: "{"},
+				{Type: token.IDENT, Literal: "π"},
+				{Type: token.RBRACE, Literal: "}"},
+				{Type: token.ELEMENT_CLOSE_START, Literal: "</"},
+				{Type: token.ELEMENT_IDENT, Literal: "p"},
+				{Type: token.ELEMENT_CLOSE_END, Literal: ">"},
+				{Type: token.ILLEGAL, Literal: "½"},
+				{Type: token.EOF},
+			},
+		},
+
+		{
+			name: "Comments",
+			input: `
+			// line comment
+			let a = 1 / 2 /* block
+			comment */ + 3
+			/// Doc comment
+			//// not a doc comment
+			fn
+			`,
+			want: []token.Token{
+				{Type: token.LET, Literal: "let", Trivia: []token.Token{
+					{Type: token.COMMENT, Literal: "// line comment"},
+				}},
+				{Type: token.IDENT, Literal: "a"},
+				{Type: token.ASSIGN, Literal: "="},
+				{Type: token.INT, Literal: "1"},
+				{Type: token.DIV, Literal: "/"},
+				{Type: token.INT, Literal: "2"},
+				{Type: token.PLUS, Literal: "+", Trivia: []token.Token{
+					{Type: token.COMMENT, Literal: "/* block\n\t\t\tcomment */"},
+				}},
+				{Type: token.INT, Literal: "3"},
+				{Type: token.FUNC, Literal: "fn", Trivia: []token.Token{
+					{Type: token.DOC_COMMENT, Literal: "/// Doc comment"},
+					{Type: token.COMMENT, Literal: "//// not a doc comment"},
+				}},
 				{Type: token.EOF},
 			},
 		},
@@ -260,6 +411,87 @@ func TestNextToken(t *testing.T) {
 				{Type: token.EOF},
 			},
 		},
+		{
+			name:  "Fragments",
+			input: `<><p>a</p>b</>`,
+			want: []token.Token{
+				{Type: token.ELEMENT_OPEN_START, Literal: "<"},
+				{Type: token.ELEMENT_OPEN_END, Literal: ">"},
+				{Type: token.ELEMENT_OPEN_START, Literal: "<"},
+				{Type: token.ELEMENT_IDENT, Literal: "p"},
+				{Type: token.ELEMENT_OPEN_END, Literal: ">"},
+				{Type: token.ELEMENT_TEXT, Literal: "a"},
+				{Type: token.ELEMENT_CLOSE_START, Literal: "</"},
+				{Type: token.ELEMENT_IDENT, Literal: "p"},
+				{Type: token.ELEMENT_CLOSE_END, Literal: ">"},
+				{Type: token.ELEMENT_TEXT, Literal: "b"},
+				{Type: token.ELEMENT_CLOSE_START, Literal: "</"},
+				{Type: token.ELEMENT_CLOSE_END, Literal: ">"},
+				{Type: token.EOF},
+			},
+		},
+		{
+			name:  "Control flow in element children",
+			input: `<p>{if t > 0 { Time: {t} } else { <b/> }}{for x in xs {{x}}}</p>`,
+			want: []token.Token{
+				{Type: token.ELEMENT_OPEN_START, Literal: "<"},
+				{Type: token.ELEMENT_IDENT, Literal: "p"},
+				{Type: token.ELEMENT_OPEN_END, Literal: ">"},
+				{Type: token.LBRACE, Literal: "{"},
+				{Type: token.IF, Literal: "if"},
+				{Type: token.IDENT, Literal: "t"},
+				{Type: token.RANGLE, Literal: ">"},
+				{Type: token.INT, Literal: "0"},
+				{Type: token.LBRACE, Literal: "{"},
+				{Type: token.ELEMENT_TEXT, Literal: " Time: "},
+				{Type: token.LBRACE, Literal: "{"},
+				{Type: token.IDENT, Literal: "t"},
+				{Type: token.RBRACE, Literal: "}"},
+				{Type: token.ELEMENT_TEXT, Literal: " "},
+				{Type: token.RBRACE, Literal: "}"},
+				{Type: token.ELSE, Literal: "else"},
+				{Type: token.LBRACE, Literal: "{"},
+				{Type: token.ELEMENT_TEXT, Literal: " "},
+				{Type: token.ELEMENT_OPEN_START, Literal: "<"},
+				{Type: token.ELEMENT_IDENT, Literal: "b"},
+				{Type: token.ELEMENT_VOID_END, Literal: "/>"},
+				{Type: token.ELEMENT_TEXT, Literal: " "},
+				{Type: token.RBRACE, Literal: "}"},
+				{Type: token.RBRACE, Literal: "}"},
+				{Type: token.LBRACE, Literal: "{"},
+				{Type: token.FOR, Literal: "for"},
+				{Type: token.IDENT, Literal: "x"},
+				{Type: token.IN, Literal: "in"},
+				{Type: token.IDENT, Literal: "xs"},
+				{Type: token.LBRACE, Literal: "{"},
+				{Type: token.LBRACE, Literal: "{"},
+				{Type: token.IDENT, Literal: "x"},
+				{Type: token.RBRACE, Literal: "}"},
+				{Type: token.RBRACE, Literal: "}"},
+				{Type: token.RBRACE, Literal: "}"},
+				{Type: token.ELEMENT_CLOSE_START, Literal: "</"},
+				{Type: token.ELEMENT_IDENT, Literal: "p"},
+				{Type: token.ELEMENT_CLOSE_END, Literal: ">"},
+				{Type: token.EOF},
+			},
+		},
+		{
+			name:  "Spread attributes",
+			input: `<a {...props} href="/" />`,
+			want: []token.Token{
+				{Type: token.ELEMENT_OPEN_START, Literal: "<"},
+				{Type: token.ELEMENT_IDENT, Literal: "a"},
+				{Type: token.LBRACE, Literal: "{"},
+				{Type: token.ELLIPSIS, Literal: "..."},
+				{Type: token.IDENT, Literal: "props"},
+				{Type: token.RBRACE, Literal: "}"},
+				{Type: token.ELEMENT_ATTR, Literal: "href"},
+				{Type: token.ASSIGN, Literal: "="},
+				{Type: token.STRING, Literal: `"/"`},
+				{Type: token.ELEMENT_VOID_END, Literal: "/>"},
+				{Type: token.EOF},
+			},
+		},
 		{
 			name:  "Function declaration",
 			input: `fn print() {}`,
@@ -365,21 +597,312 @@ func TestNextToken(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			lex := New([]byte(tt.input))
+			tokens := New([]byte(tt.input)).Tokens()
+
+			if diff := cmp.Diff(tt.want, tokens, cmpOpts...); diff != "" {
+				t.Errorf("Tokenize() mismatch (-want +got):\nInput:%s\n%s", tt.input, diff)
+			}
+		})
+	}
+}
+
+// TestNextToken_ElementDisambiguation covers inputs where '<' could open an
+// element, compare two values or start type parameters.
+func TestNextToken_ElementDisambiguation(t *testing.T) {
+	const (
+		open  = token.ELEMENT_OPEN_START
+		name  = token.ELEMENT_IDENT
+		end   = token.ELEMENT_OPEN_END
+		close = token.ELEMENT_CLOSE_START
+		cend  = token.ELEMENT_CLOSE_END
+		void  = token.ELEMENT_VOID_END
+		text  = token.ELEMENT_TEXT
+	)
+
+	tests := []struct {
+		input string
+		want  []token.TokenType
+	}{
+		// Comparisons
+		{`a < b`, []token.TokenType{token.IDENT, token.LANGLE, token.IDENT}},
+		{`a <b`, []token.TokenType{token.IDENT, token.LANGLE, token.IDENT}},
+		{`a<b>c`, []token.TokenType{token.IDENT, token.LANGLE, token.IDENT, token.RANGLE, token.IDENT}},
+		{`1 <x`, []token.TokenType{token.INT, token.LANGLE, token.IDENT}},
+		{`1.5 <x`, []token.TokenType{token.FLOAT, token.LANGLE, token.IDENT}},
+		{`"a" <b`, []token.TokenType{token.STRING, token.LANGLE, token.IDENT}},
+		{`'a' <b`, []token.TokenType{token.CHAR, token.LANGLE, token.IDENT}},
+		{`true <b`, []token.TokenType{token.BOOL, token.LANGLE, token.IDENT}},
+		{"`a` <b", []token.TokenType{token.BACKTICK, token.TEMPLATE_TEXT, token.BACKTICK, token.LANGLE, token.IDENT}},
+		{`f() <b`, []token.TokenType{token.IDENT, token.LPAREN, token.RPAREN, token.LANGLE, token.IDENT}},
+		{`a[0] <b`, []token.TokenType{token.IDENT, token.LBRACKET, token.INT, token.RBRACKET, token.LANGLE, token.IDENT}},
+		{`int(a) <b`, []token.TokenType{token.TYPE_INT, token.LPAREN, token.IDENT, token.RPAREN, token.LANGLE, token.IDENT}},
+		{`if x <y {}`, []token.TokenType{token.IF, token.IDENT, token.LANGLE, token.IDENT, token.LBRACE, token.RBRACE}},
+		{`a <= b`, []token.TokenType{token.IDENT, token.LT_EQ, token.IDENT}},
+		{`a << b`, []token.TokenType{token.IDENT, token.BITSHIFTL, token.IDENT}},
+		{`a < 1`, []token.TokenType{token.IDENT, token.LANGLE, token.INT}},
+
+		// Type parameters
+		{`foo<T>`, []token.TokenType{token.IDENT, token.LANGLE, token.IDENT, token.RANGLE}},
+		{`fn f<T>() {}`, []token.TokenType{token.FUNC, token.IDENT, token.LANGLE, token.IDENT, token.RANGLE, token.LPAREN, token.RPAREN, token.LBRACE, token.RBRACE}},
+		{`union Option<T> {}`, []token.TokenType{token.UNION, token.IDENT, token.LANGLE, token.IDENT, token.RANGLE, token.LBRACE, token.RBRACE}},
+		{`a<b>(c)`, []token.TokenType{token.IDENT, token.LANGLE, token.IDENT, token.RANGLE, token.LPAREN, token.IDENT, token.RPAREN}},
+
+		// Elements in operand position
+		{`<a/>`, []token.TokenType{open, name, void}},
+		{`return <a/>`, []token.TokenType{token.RETURN, open, name, void}},
+		{`let x = <a/>`, []token.TokenType{token.LET, token.IDENT, token.ASSIGN, open, name, void}},
+		{`(<a/>)`, []token.TokenType{token.LPAREN, open, name, void, token.RPAREN}},
+		{`[<a/>, <b/>]`, []token.TokenType{token.LBRACKET, open, name, void, token.COMMA, open, name, void, token.RBRACKET}},
+		{`f(<a/>)`, []token.TokenType{token.IDENT, token.LPAREN, open, name, void, token.RPAREN}},
+		{`x < <b/>`, []token.TokenType{token.IDENT, token.LANGLE, open, name, void}},
+		{`!<b/>`, []token.TokenType{token.BANG, open, name, void}},
+		{`{ <a/> }`, []token.TokenType{token.LBRACE, open, name, void, token.RBRACE}},
+		{"x\n<a/>", []token.TokenType{token.IDENT, open, name, void}},
+		{`<a/><b/>`, []token.TokenType{open, name, void, open, name, void}},
+		{`<a></a><b/>`, []token.TokenType{open, name, end, close, name, cend, open, name, void}},
 
-			var tokens []token.Token
+		// Element content is text wherever the element appears
+		{`fn f() { return <div>Hello</div> }`, []token.TokenType{
+			token.FUNC, token.IDENT, token.LPAREN, token.RPAREN, token.LBRACE, token.RETURN,
+			open, name, end, text, close, name, cend, token.RBRACE,
+		}},
+		{`<a>if x < y</a>`, []token.TokenType{open, name, end, text, close, name, cend}},
+		{`<a>{x <b}</a>`, []token.TokenType{open, name, end, token.LBRACE, token.IDENT, token.LANGLE, token.IDENT, token.RBRACE, close, name, cend}},
+		{`<a>{<b>c</b>}</a>`, []token.TokenType{
+			open, name, end, token.LBRACE, open, name, end, text, close, name, cend, token.RBRACE, close, name, cend,
+		}},
+		{`<a x={y > 1}>z</a>`, []token.TokenType{
+			open, name, token.ELEMENT_ATTR, token.ASSIGN, token.LBRACE, token.IDENT, token.RANGLE, token.INT, token.RBRACE, end, text, close, name, cend,
+		}},
+		{`<a x={<b/>}/>`, []token.TokenType{open, name, token.ELEMENT_ATTR, token.ASSIGN, token.LBRACE, open, name, void, token.RBRACE, void}},
+		{`<a>{ {} }</a>`, []token.TokenType{open, name, end, token.LBRACE, token.LBRACE, token.RBRACE, token.RBRACE, close, name, cend}},
 
-			for {
-				t := lex.NextToken()
-				tokens = append(tokens, t)
-				if t.Type == token.EOF {
-					break
+		// Fragments
+		{`<></>`, []token.TokenType{open, end, close, cend}},
+		{`{}<></>`, []token.TokenType{token.LBRACE, token.RBRACE, open, end, close, cend}},
+		{`return <><a/></>`, []token.TokenType{token.RETURN, open, end, open, name, void, close, cend}},
+		{`a <> b`, []token.TokenType{token.IDENT, token.LANGLE, token.RANGLE, token.IDENT}},
+		{`<a>1 <> 2</a>`, []token.TokenType{open, name, end, text, open, end, text, close, name, cend}},
+
+		// Templates nest with elements
+		{"`${<a/>}`", []token.TokenType{token.BACKTICK, token.TEMPLATE_EXPR_START, open, name, void, token.RBRACE, token.BACKTICK}},
+		{"<a>{`<b>`}</a>", []token.TokenType{open, name, end, token.LBRACE, token.BACKTICK, token.TEMPLATE_TEXT, token.BACKTICK, token.RBRACE, close, name, cend}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			var got []token.TokenType
+			for _, tok := range New([]byte(tt.input)).Tokens() {
+				if tok.Type != token.EOF {
+					got = append(got, tok.Type)
 				}
 			}
 
-			if diff := cmp.Diff(tt.want, tokens, cmpOpts...); diff != "" {
+			if diff := cmp.Diff(tt.want, got); diff != "" {
 				t.Errorf("Tokenize() mismatch (-want +got):\nInput:%s\n%s", tt.input, diff)
 			}
 		})
 	}
 }
+
+func TestNextToken_Positions(t *testing.T) {
+	input := "let é = 'ü'\n\t<b>日本</b> // ß\nx"
+	want := []token.Token{
+		{Type: token.LET, Literal: "let", Line: 0, Column: 0},
+		{Type: token.IDENT, Literal: "é", Line: 0, Column: 4},
+		{Type: token.ASSIGN, Literal: "=", Line: 0, Column: 6},
+		{Type: token.CHAR, Literal: "'ü'", Line: 0, Column: 8},
+		{Type: token.ELEMENT_OPEN_START, Literal: "<", Line: 1, Column: 1},
+		{Type: token.ELEMENT_IDENT, Literal: "b", Line: 1, Column: 2},
+		{Type: token.ELEMENT_OPEN_END, Literal: ">", Line: 1, Column: 3},
+		{Type: token.ELEMENT_TEXT, Literal: "日本", Line: 1, Column: 4},
+		{Type: token.ELEMENT_CLOSE_START, Literal: "</", Line: 1, Column: 6},
+		{Type: token.ELEMENT_IDENT, Literal: "b", Line: 1, Column: 8},
+		{Type: token.ELEMENT_CLOSE_END, Literal: ">", Line: 1, Column: 9},
+		{Type: token.IDENT, Literal: "x", Line: 2, Column: 0, Trivia: []token.Token{
+			{Type: token.COMMENT, Literal: "// ß", Line: 1, Column: 11},
+		}},
+		{Type: token.EOF, Line: 2, Column: 1},
+	}
+
+	tokens := New([]byte(input)).Tokens()
+
+	if diff := cmp.Diff(want, tokens); diff != "" {
+		t.Errorf("Tokenize() mismatch (-want +got):\nInput:%s\n%s", input, diff)
+	}
+}
+
+func TestNextToken_Diagnostics(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{
+			name:  "Valid input",
+			input: `let a = <div class="x"><b>{"hi"}</b></div>`,
+			want:  nil,
+		},
+		{
+			name:  "Unterminated string",
+			input: "let a = 1\nlet b = \"abc",
+			want:  []string{"1:8: Unterminated string literal"},
+		},
+		{
+			name:  "Unterminated char",
+			input: "'a",
+			want:  []string{"0:0: Unterminated char literal"},
+		},
+		{
+			name:  "Unterminated block comment",
+			input: "a /* b",
+			want:  []string{"0:2: Unterminated block comment"},
+		},
+		{
+			name:  "Unterminated template string",
+			input: "let a = `x ${b}",
+			want:  []string{"0:8: Unterminated template string"},
+		},
+		{
+			name:  "Stray characters",
+			input: "let a = 1 # 2 @",
+			want: []string{
+				"0:10: Unexpected character '#'",
+				"0:14: Unexpected character '@'",
+			},
+		},
+		{
+			name:  "Unclosed elements",
+			input: "<div>\n  <p>text",
+			want: []string{
+				"0:1: Unclosed element <div>",
+				"1:3: Unclosed element <p>",
+			},
+		},
+		{
+			name:  "Mismatched closing tag",
+			input: "<div><p></div></p>",
+			want: []string{
+				"0:10: Mismatched closing tag </div>, expected </p>",
+				"0:16: Mismatched closing tag </p>, expected </div>",
+			},
+		},
+		{
+			name:  "Mismatched fragment",
+			input: "<><p></><p></p>",
+			want: []string{
+				"0:7: Mismatched closing tag </>, expected </p>",
+				"0:0: Unclosed element <>",
+			},
+		},
+		{
+			name:  "Malformed closing tag",
+			input: "<div></div <p/>",
+			want: []string{
+				"0:11: Expected '>' to close </div",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lex := New([]byte(tt.input))
+			lex.Tokens()
+
+			var got []string
+			for _, msg := range lex.Diagnostics.Messages() {
+				got = append(got, fmt.Sprintf("%d:%d: %s", msg.Line, msg.Column, msg.Text))
+			}
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Diagnostics mismatch (-want +got):\nInput:%s\n%s", tt.input, diff)
+			}
+		})
+	}
+}
+
+func TestNewReader(t *testing.T) {
+	input := "fn App() Element {\n\treturn <p class=\"é\">Grüße {name}</p>\n}"
+	want := New([]byte(input)).Tokens()
+
+	// Reading one byte at a time splits multi-byte characters across reads
+	lex := NewReader(iotest.OneByteReader(strings.NewReader(input)))
+	got := lex.Tokens()
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("NewReader().Tokens() mismatch (-want +got):\n%s", diff)
+	}
+	if err := lex.Err(); err != nil {
+		t.Errorf("NewReader().Err() = %v, want nil", err)
+	}
+}
+
+func TestNewReader_Error(t *testing.T) {
+	readErr := errors.New("disk on fire")
+	lex := NewReader(io.MultiReader(strings.NewReader("let a"), iotest.ErrReader(readErr)))
+
+	var types []token.TokenType
+	for tok := range lex.All() {
+		types = append(types, tok.Type)
+	}
+
+	want := []token.TokenType{token.LET, token.IDENT, token.EOF}
+	if diff := cmp.Diff(want, types); diff != "" {
+		t.Errorf("NewReader().All() mismatch (-want +got):\n%s", diff)
+	}
+	if err := lex.Err(); !errors.Is(err, readErr) {
+		t.Errorf("NewReader().Err() = %v, want %v", err, readErr)
+	}
+}
+
+func TestAll_StopsEarly(t *testing.T) {
+	lex := New([]byte("a b c"))
+	for tok := range lex.All() {
+		if tok.Literal == "b" {
+			break
+		}
+	}
+
+	if tok := lex.NextToken(); tok.Literal != "c" {
+		t.Errorf("NextToken() after break = %q, want %q", tok.Literal, "c")
+	}
+}
+
+func FuzzNextToken(f *testing.F) {
+	seeds := []string{
+		"",
+		"fn App<T>(value: T) Element { return (<Message value={value} />) }",
+		`<h1>{message}</h1><button type="button" disabled={true}>Hello, {name}!</button>`,
+		"let a = `x ${b + `y ${c}`}` /* unterminated",
+		"0x1F 1.5e-3 'a' '\\u{1F600}' \"\\",
+		"<div>{if a < b { <b>é</b> }}</",
+		"\xff\xfe<\x00>",
+	}
+	for _, seed := range seeds {
+		f.Add([]byte(seed))
+	}
+
+	f.Fuzz(func(t *testing.T, input []byte) {
+		lex := New(input)
+
+		// Every token consumes input, so EOF must be reached within a bounded
+		// number of tokens.
+		var tokens []token.Token
+		limit := 2*len(input) + 2
+		for range limit {
+			tokens = append(tokens, lex.NextToken())
+			if tokens[len(tokens)-1].Type == token.EOF {
+				break
+			}
+		}
+		if tokens[len(tokens)-1].Type != token.EOF {
+			t.Fatalf("lexer did not reach EOF after %d tokens for input %q", limit, input)
+		}
+
+		// Streaming the input must not change the result
+		streamed := NewReader(iotest.OneByteReader(bytes.NewReader(input))).Tokens()
+		if diff := cmp.Diff(tokens, streamed); diff != "" {
+			t.Fatalf("NewReader() mismatch (-New +NewReader) for input %q:\n%s", input, diff)
+		}
+	})
+}
@@ -0,0 +1,216 @@
+package parser
+
+import (
+	"gloss/ast"
+	"gloss/token"
+	"slices"
+	"strings"
+)
+
+// parseElement parses an element or fragment starting at the current
+// ELEMENT_OPEN_START token. Mismatched and unclosed tags are reported by the
+// lexer.
+func (p *Parser) parseElement() ast.Expression {
+	el := &ast.Element{Token: p.curToken}
+	if p.peekToken.Type == token.ELEMENT_IDENT {
+		p.nextToken()
+		el.Name = p.curToken.Literal
+	}
+
+	for p.peekToken.Type == token.ELEMENT_ATTR || p.peekToken.Type == token.LBRACE {
+		p.nextToken()
+		attr := p.parseAttribute()
+		switch {
+		case attr == nil:
+		case attr.Name == "key" && !attr.Spread:
+			if attr.Value == nil {
+				p.Diagnostics.Error(p.curToken, "Expected a value for key")
+			}
+			el.Key = attr.Value
+		default:
+			el.Attributes = append(el.Attributes, attr)
+		}
+	}
+
+	if p.peekToken.Type == token.ELEMENT_VOID_END {
+		p.nextToken()
+		return el
+	}
+
+	if !p.expectNext(token.ELEMENT_OPEN_END, "Expected '>'") {
+		return el
+	}
+
+	el.Children = p.parseElementChildren(token.ELEMENT_CLOSE_START)
+
+	if p.peekToken.Type == token.ELEMENT_CLOSE_START {
+		p.nextToken()
+		if p.peekToken.Type == token.ELEMENT_IDENT {
+			p.nextToken()
+		}
+		if p.peekToken.Type == token.ELEMENT_CLOSE_END {
+			p.nextToken()
+		}
+	}
+	return el
+}
+
+// parseAttribute parses a named attribute, e.g. id="app" or id={id}, or a
+// spread attribute, e.g. {...props}.
+func (p *Parser) parseAttribute() *ast.Attribute {
+	if p.curToken.Type == token.LBRACE {
+		if !p.expectNext(token.ELLIPSIS, "Expected '...' in spread attribute") {
+			// Skip the expression to carry on with the following attributes
+			for p.curToken.Type != token.RBRACE && p.curToken.Type != token.EOF {
+				p.nextToken()
+			}
+			return nil
+		}
+		p.nextToken()
+		attr := &ast.Attribute{Spread: true, Value: p.parseExpression(LOWEST)}
+		p.expectNext(token.RBRACE, "Expected '}'")
+		return attr
+	}
+
+	attr := &ast.Attribute{Name: p.curToken.Literal}
+	if p.peekToken.Type != token.ASSIGN {
+		return attr
+	}
+	p.nextToken()
+	p.nextToken()
+
+	switch p.curToken.Type {
+	case token.STRING:
+		attr.Value = p.parseStringLiteral()
+	case token.LBRACE:
+		p.nextToken()
+		attr.Value = p.parseExpression(LOWEST)
+		p.expectNext(token.RBRACE, "Expected '}'")
+	default:
+		p.Diagnostics.Error(p.curToken, "Expected attribute value")
+	}
+	return attr
+}
+
+// parseElementChildren parses children up to the end token, which is left as
+// the peek token.
+func (p *Parser) parseElementChildren(end token.TokenType) []ast.Expression {
+	var children []ast.Expression
+	for p.peekToken.Type != end && p.peekToken.Type != token.EOF {
+		p.nextToken()
+		if child := p.parseElementChild(); child != nil {
+			children = append(children, child)
+		}
+	}
+	return children
+}
+
+// parseElementChild parses the text, element or {expression} at the current
+// token. Whitespace only text and empty expressions produce no child.
+func (p *Parser) parseElementChild() ast.Expression {
+	switch p.curToken.Type {
+	case token.ELEMENT_TEXT:
+		if text := elementText(p.curToken.Literal); text != "" {
+			return &ast.StringLiteral{Value: text}
+		}
+		return nil
+	case token.ELEMENT_OPEN_START:
+		return p.parseElement()
+	case token.LBRACE:
+		if p.peekToken.Type == token.RBRACE {
+			p.nextToken()
+			return nil
+		}
+		p.nextToken()
+
+		var expr ast.Expression
+		switch p.curToken.Type {
+		case token.IF:
+			expr = p.parseElementIf()
+		case token.FOR:
+			expr = p.parseElementFor()
+		default:
+			expr = p.parseExpression(LOWEST)
+		}
+		p.expectNext(token.RBRACE, "Expected '}'")
+		return expr
+	default:
+		p.Diagnostics.Error(p.curToken, "Unexpected token in element")
+		return nil
+	}
+}
+
+// elementText collapses the whitespace of element text so that markup can be
+// indented freely. Lines are trimmed, blank lines dropped and the remaining
+// lines joined with a space. Text on a single line is kept as written.
+func elementText(s string) string {
+	lines := strings.Split(s, "\n")
+	var text []string
+	for i, line := range lines {
+		if i > 0 {
+			line = strings.TrimLeft(line, " \t\r")
+		}
+		if i < len(lines)-1 {
+			line = strings.TrimRight(line, " \t\r")
+		}
+		if line != "" {
+			text = append(text, line)
+		}
+	}
+	return strings.Join(text, " ")
+}
+
+func (p *Parser) parseElementIf() ast.Expression {
+	expr := &ast.ElementIf{}
+	p.nextToken()
+	expr.Condition = p.parseExpression(LOWEST)
+	expr.Then = p.parseElementBody()
+
+	if p.peekToken.Type == token.ELSE {
+		p.nextToken()
+		if p.peekToken.Type == token.IF {
+			p.nextToken()
+			expr.Else = p.parseElementIf()
+		} else {
+			expr.Else = &ast.Element{Children: p.parseElementBody()}
+		}
+	}
+	return expr
+}
+
+func (p *Parser) parseElementFor() ast.Expression {
+	expr := &ast.ElementFor{}
+	if !p.expectNext(token.IDENT, "Expected name") {
+		return nil
+	}
+	var ok bool
+	expr.Key, expr.Item, expr.Iterable, ok = p.parseForIn()
+	if !ok {
+		return nil
+	}
+	expr.Body = p.parseElementBody()
+	return expr
+}
+
+// parseElementBody parses the element children between the braces following
+// the current token. Whitespace around the children is not significant.
+func (p *Parser) parseElementBody() []ast.Expression {
+	if !p.expectNext(token.LBRACE, "Expected '{'") {
+		return nil
+	}
+	children := p.parseElementChildren(token.RBRACE)
+	p.expectNext(token.RBRACE, "Expected '}'")
+
+	if len(children) > 0 {
+		if text, ok := children[0].(*ast.StringLiteral); ok {
+			text.Value = strings.TrimLeft(text.Value, " \t")
+		}
+		if text, ok := children[len(children)-1].(*ast.StringLiteral); ok {
+			text.Value = strings.TrimRight(text.Value, " \t")
+		}
+	}
+	return slices.DeleteFunc(children, func(child ast.Expression) bool {
+		text, ok := child.(*ast.StringLiteral)
+		return ok && text.Value == ""
+	})
+}
@@ -0,0 +1,92 @@
+package parser
+
+import (
+	"fmt"
+	"gloss/token"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+var simpleEscapes = map[byte]rune{
+	'n':  '\n',
+	't':  '\t',
+	'r':  '\r',
+	'0':  0,
+	'\\': '\\',
+	'"':  '"',
+	'\'': '\'',
+}
+
+// unescape decodes the escape sequences in s, the contents of tok without its
+// quotes. Invalid escapes are reported and dropped from the result.
+func (p *Parser) unescape(tok token.Token, s string) string {
+	if !strings.ContainsRune(s, '\\') {
+		return s
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' {
+			b.WriteByte(s[i])
+			continue
+		}
+
+		// Position of the escape within the source, skipping the opening quote
+		pos := tok
+		pos.Column = tok.Column + 1 + utf8.RuneCountInString(s[:i])
+
+		if i+1 >= len(s) {
+			p.Diagnostics.Error(pos, "Unterminated escape sequence")
+			break
+		}
+
+		if r, ok := simpleEscapes[s[i+1]]; ok {
+			b.WriteRune(r)
+			i++
+			continue
+		}
+
+		if s[i+1] == 'u' {
+			r, n, ok := decodeUnicodeEscape(s[i+2:])
+			if !ok {
+				p.Diagnostics.Error(pos, fmt.Sprintf("Invalid unicode escape '\\u%s'", s[i+2:i+2+n]))
+			} else {
+				b.WriteRune(r)
+			}
+			i += 1 + n
+			continue
+		}
+
+		_, size := utf8.DecodeRuneInString(s[i+1:])
+		p.Diagnostics.Error(pos, fmt.Sprintf("Invalid escape sequence '\\%s'", s[i+1:i+1+size]))
+		i += size
+	}
+
+	return b.String()
+}
+
+// decodeUnicodeEscape decodes the "{XXXX}" part of a \u{XXXX} escape. It
+// returns the number of bytes consumed, even when the escape is invalid.
+func decodeUnicodeEscape(s string) (rune, int, bool) {
+	if !strings.HasPrefix(s, "{") {
+		return 0, 0, false
+	}
+
+	end := strings.IndexByte(s, '}')
+	if end < 0 {
+		return 0, len(s), false
+	}
+
+	digits := s[1:end]
+	if len(digits) == 0 || len(digits) > 6 {
+		return 0, end + 1, false
+	}
+
+	val, err := strconv.ParseUint(digits, 16, 32)
+	if err != nil || !utf8.ValidRune(rune(val)) {
+		return 0, end + 1, false
+	}
+
+	return rune(val), end + 1, true
+}
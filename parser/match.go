@@ -0,0 +1,139 @@
+package parser
+
+import (
+	"gloss/ast"
+	"gloss/token"
+)
+
+func (p *Parser) parseMatchExpression() ast.Expression {
+	return p.parseMatch()
+}
+
+// parseMatch parses a match starting at the current MATCH token. Arms are
+// separated by commas or new lines, and evaluate either an expression or a
+// block.
+func (p *Parser) parseMatch() *ast.Match {
+	m := &ast.Match{Token: p.curToken}
+	p.nextToken()
+	m.Subject = p.parseExpression(LOWEST)
+
+	if !p.expectNext(token.LBRACE, "Expected '{'") {
+		return m
+	}
+
+	for p.peekToken.Type != token.RBRACE && p.peekToken.Type != token.EOF {
+		p.nextToken()
+		arm := &ast.MatchArm{Token: p.curToken}
+		arm.Pattern = p.parsePattern()
+		if arm.Pattern == nil {
+			// Skip the rest of the pattern to carry on with the arm
+			for p.peekToken.Type != token.FAT_ARROW && p.peekToken.Type != token.RBRACE && p.peekToken.Type != token.EOF {
+				p.nextToken()
+			}
+		}
+		if !p.expectNext(token.FAT_ARROW, "Expected '=>'") {
+			return m
+		}
+
+		if p.peekToken.Type == token.LBRACE {
+			p.nextToken()
+			arm.Body = p.parseBlockStatement()
+		} else {
+			p.nextToken()
+			arm.Value = p.parseExpression(LOWEST)
+		}
+		m.Arms = append(m.Arms, arm)
+
+		if p.peekToken.Type == token.COMMA {
+			p.nextToken()
+		}
+	}
+
+	p.expectNext(token.RBRACE, "Expected '}'")
+	return m
+}
+
+// parsePattern parses the pattern starting at the current token.
+func (p *Parser) parsePattern() ast.Pattern {
+	switch p.curToken.Type {
+	case token.INT, token.FLOAT, token.STRING, token.CHAR, token.BOOL:
+		return &ast.LiteralPattern{Value: p.unaryExprParseFunc[p.curToken.Type]()}
+
+	case token.MINUS:
+		if p.peekToken.Type != token.INT && p.peekToken.Type != token.FLOAT {
+			p.Diagnostics.Error(p.peekToken, "Expected number after '-' in pattern")
+			return nil
+		}
+		return &ast.LiteralPattern{Value: p.parseUnaryExpression()}
+
+	case token.LPAREN:
+		items := p.parsePatternList()
+		if len(items) == 1 {
+			return items[0]
+		}
+		return &ast.TuplePattern{Items: items}
+
+	case token.IDENT:
+		return p.parseVariantPattern()
+
+	default:
+		p.Diagnostics.Error(p.curToken, "Expected pattern")
+		return nil
+	}
+}
+
+// parseVariantPattern parses the patterns starting with a name: wildcards,
+// bindings, enum members and union variants.
+func (p *Parser) parseVariantPattern() ast.Pattern {
+	name := p.curToken.Literal
+	pat := &ast.VariantPattern{Token: p.curToken, Name: name}
+
+	qualified := p.peekToken.Type == token.PERIOD
+	if qualified {
+		p.nextToken()
+		if !p.expectNext(token.IDENT, "Expected variant name after '.'") {
+			return nil
+		}
+		pat.Type = name
+		pat.Name = p.curToken.Literal
+	}
+
+	if p.peekToken.Type == token.LPAREN {
+		p.nextToken()
+		items := p.parsePatternList()
+		switch len(items) {
+		case 0:
+			p.Diagnostics.Error(p.curToken, "Expected pattern")
+		case 1:
+			pat.Payload = items[0]
+		default:
+			pat.Payload = &ast.TuplePattern{Items: items}
+		}
+		return pat
+	}
+
+	switch {
+	case qualified:
+		return pat
+	case name == "_":
+		return &ast.WildcardPattern{}
+	default:
+		return &ast.BindingPattern{Token: pat.Token, Name: name}
+	}
+}
+
+// parsePatternList parses the comma separated patterns between the current
+// '(' and its closing ')'.
+func (p *Parser) parsePatternList() []ast.Pattern {
+	var items []ast.Pattern
+	for p.peekToken.Type != token.RPAREN && p.peekToken.Type != token.EOF {
+		p.nextToken()
+		items = append(items, p.parsePattern())
+		if p.peekToken.Type != token.COMMA {
+			break
+		}
+		p.nextToken()
+	}
+	p.expectNext(token.RPAREN, "Expected ')'")
+	return items
+}
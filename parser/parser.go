@@ -1,11 +1,16 @@
 package parser
 
 import (
+	"errors"
+	"fmt"
 	"gloss/ast"
 	"gloss/diagnostic"
 	"gloss/lexer"
 	"gloss/token"
+	"math"
 	"strconv"
+	"strings"
+	"unicode/utf8"
 )
 
 type (
@@ -21,31 +26,44 @@ type Parser struct {
 
 	Diagnostics *diagnostic.MessageList
 
-	unaryExprParseFunc  map[token.TokenType]unaryExprParseFunc
-	binaryExprParseFunc map[token.TokenType]binaryExprParseFunc
+	unaryExprParseFunc  [token.NumTokens]unaryExprParseFunc
+	binaryExprParseFunc [token.NumTokens]binaryExprParseFunc
 }
 
 func NewParser(l *lexer.Lexer) *Parser {
 	p := &Parser{
 		lexer:       l,
-		Diagnostics: &diagnostic.MessageList{},
+		Diagnostics: l.Diagnostics, // Report lexing and parsing errors together
 	}
 	p.init()
 	return p
 }
 
 func (p *Parser) init() {
-	p.unaryExprParseFunc = map[token.TokenType]unaryExprParseFunc{
-		token.BOOL:   p.parseBoolean,
-		token.INT:    p.parseIntegerLiteral,
-		token.STRING: p.parseStringLiteral,
-		token.IDENT:  p.parseIdent,
-		token.MINUS:  p.parseUnaryExpression,
-		token.BANG:   p.parseUnaryExpression,
-		token.LPAREN: p.parseGroupedExpression,
+	p.unaryExprParseFunc = [token.NumTokens]unaryExprParseFunc{
+		token.BOOL:     p.parseBoolean,
+		token.INT:      p.parseIntegerLiteral,
+		token.FLOAT:    p.parseFloatLiteral,
+		token.STRING:   p.parseStringLiteral,
+		token.BACKTICK: p.parseTemplateLiteral,
+		token.CHAR:     p.parseCharLiteral,
+
+		// Conversions
+		token.TYPE_INT:    p.parseTypeConversion,
+		token.TYPE_FLOAT:  p.parseTypeConversion,
+		token.TYPE_CHAR:   p.parseTypeConversion,
+		token.TYPE_STRING: p.parseTypeConversion,
+		token.IDENT:       p.parseIdent,
+		token.MINUS:       p.parseUnaryExpression,
+		token.BANG:        p.parseUnaryExpression,
+		token.LPAREN:      p.parseGroupedExpression,
+
+		token.ELEMENT_OPEN_START: p.parseElement,
+		token.MATCH:              p.parseMatchExpression,
+		token.FUNC:               p.parseFuncLiteral,
 	}
 
-	p.binaryExprParseFunc = map[token.TokenType]binaryExprParseFunc{
+	p.binaryExprParseFunc = [token.NumTokens]binaryExprParseFunc{
 		// Mathmatical
 		token.PLUS:  p.parseBinaryExpression,
 		token.MINUS: p.parseBinaryExpression,
@@ -75,7 +93,13 @@ func (p *Parser) init() {
 		token.BITSHIFTL:   p.parseBinaryExpression,
 		token.BITSHIFTR:   p.parseBinaryExpression,
 
-		token.LPAREN: p.parseCallExpression,
+		// Ranges
+		token.RANGE:      p.parseRangeExpression,
+		token.RANGE_INCL: p.parseRangeExpression,
+
+		token.LPAREN:   p.parseCallExpression,
+		token.PERIOD:   p.parseMemberExpression,
+		token.LBRACKET: p.parseIndexExpression,
 	}
 	p.nextToken()
 	p.nextToken()
@@ -97,18 +121,25 @@ func (p *Parser) expectNext(t token.TokenType, msg string) bool {
 	return false
 }
 
-func (p *Parser) peekPrecedence() int {
-	if prec, ok := precedences[p.peekToken.Type]; ok {
-		return prec
+// docComment returns the text of the doc comments attached to the current
+// token, with the leading "///" removed from each line.
+func (p *Parser) docComment() string {
+	var lines []string
+	for _, t := range p.curToken.Trivia {
+		if t.Type == token.DOC_COMMENT {
+			line := strings.TrimPrefix(t.Literal, "///")
+			lines = append(lines, strings.TrimPrefix(line, " "))
+		}
 	}
-	return LOWEST
+	return strings.Join(lines, "\n")
+}
+
+func (p *Parser) peekPrecedence() int {
+	return p.peekToken.Type.Precedence()
 }
 
 func (p *Parser) curPrecedence() int {
-	if prec, ok := precedences[p.curToken.Type]; ok {
-		return prec
-	}
-	return LOWEST
+	return p.curToken.Type.Precedence()
 }
 
 // Top Level Parsing
@@ -125,25 +156,41 @@ func (p *Parser) Parse() ast.SourceFile {
 	return file
 }
 
+// node returns n as a node, or nil if n failed to parse, so that nil
+// pointers are not added to the tree as non-nil nodes.
+func node[T any, P interface {
+	*T
+	ast.Node
+}](n P) ast.Node {
+	if n == nil {
+		return nil
+	}
+	return n
+}
+
 func (p *Parser) parseDeclarations() ast.Node {
 	switch p.curToken.Type {
 	case token.ENUM:
-		return p.parseEnum()
+		return node(p.parseEnum())
 	case token.UNION:
-		return p.parseUnion()
+		return node(p.parseUnion())
 	case token.STRUCT:
-		return p.parseStruct()
+		return node(p.parseStruct())
+	case token.INTERFACE:
+		return node(p.parseInterface())
 	case token.LET:
-		return p.parseLetStatement()
+		return node(p.parseLetStatement())
 	case token.FUNC:
-		return p.parseFunc()
+		return node(p.parseFunc())
 	// TODO: Following only present to support testing, should move to parseStatements only
 	case token.IF:
-		return p.parseIfStatement()
+		return node(p.parseIfStatement())
 	case token.LOOP:
-		return p.parseLoopStatement()
+		return node(p.parseLoopStatement())
 	case token.FOR:
 		return p.parseForStatement()
+	case token.MATCH:
+		return node(p.parseMatch())
 	default:
 		// TODO: Raise error
 		return nil
@@ -153,31 +200,63 @@ func (p *Parser) parseDeclarations() ast.Node {
 func (p *Parser) parseStatements() ast.Node {
 	switch p.curToken.Type {
 	case token.LET:
-		return p.parseLetStatement()
+		return node(p.parseLetStatement())
 	case token.LOOP:
-		return p.parseLoopStatement()
+		return node(p.parseLoopStatement())
 	case token.FOR:
 		return p.parseForStatement()
 	case token.IF:
-		return p.parseIfStatement()
+		return node(p.parseIfStatement())
+	case token.MATCH:
+		return node(p.parseMatch())
 	case token.RETURN:
-		return p.parseReturnStatement()
+		return node(p.parseReturnStatement())
 	case token.BREAK:
 		return &ast.BreakStatement{}
 	case token.CONTINUE:
 		return &ast.ContinueStatement{}
 	default:
-		// TODO: Raise error
-		return nil
+		tok := p.curToken
+		exp := p.parseExpression(LOWEST)
+		switch {
+		case exp == nil:
+			// TODO: Raise error
+			return nil
+		case p.peekToken.Type.IsAssignment():
+			if stmt := p.parseAssignStatement(exp); stmt != nil {
+				return stmt
+			}
+			return nil
+		default:
+			return &ast.ExpressionStatement{Token: tok, Expression: exp}
+		}
 	}
 }
 
 func (p *Parser) parseLetStatement() *ast.LetStatement {
-	let := &ast.LetStatement{}
+	let := &ast.LetStatement{Doc: p.docComment()}
+	if p.peekToken.Type == token.MUT {
+		p.nextToken()
+		let.Mutable = true
+	}
 	if !p.expectNext(token.IDENT, "Expected name") {
 		return nil
 	}
-	let.Name = &ast.Identifier{Name: p.curToken.Literal}
+	let.Name = &ast.Identifier{Token: p.curToken, Name: p.curToken.Literal}
+
+	if p.peekToken.Type == token.COLON {
+		p.nextToken()
+		p.nextToken()
+		if let.Type = p.parseType(); let.Type == nil {
+			p.Diagnostics.Error(p.curToken, "Expected type")
+			return nil
+		}
+
+		// Variables with a type may be declared without a value
+		if p.peekToken.Type != token.ASSIGN {
+			return let
+		}
+	}
 
 	if !p.expectNext(token.ASSIGN, "Expected '='") {
 		return nil
@@ -188,10 +267,11 @@ func (p *Parser) parseLetStatement() *ast.LetStatement {
 }
 
 func (p *Parser) parseFunc() *ast.Func {
-	fn := &ast.Func{}
+	fn := &ast.Func{Doc: p.docComment()}
 	if !p.expectNext(token.IDENT, "Expected name") {
 		return nil
 	}
+	fn.Token = p.curToken
 	fn.Name = p.curToken.Literal
 
 	if p.peekToken.Type == token.LANGLE {
@@ -204,6 +284,16 @@ func (p *Parser) parseFunc() *ast.Func {
 	}
 	fn.Params = p.parseFuncParams()
 
+	// Methods declared outside of the body of their type, e.g.
+	// fn area() for Rect int {}
+	if p.peekToken.Type == token.FOR {
+		p.nextToken()
+		if !p.expectNext(token.IDENT, "Expected type name after 'for'") {
+			return nil
+		}
+		fn.Receiver = p.parseType().(*ast.TypeIdentifier)
+	}
+
 	if p.peekToken.Type != token.LBRACE {
 		p.nextToken()
 		fn.ReturnType = p.parseType()
@@ -216,25 +306,92 @@ func (p *Parser) parseFunc() *ast.Func {
 	return fn
 }
 
+// parseFuncLiteral parses an anonymous function, e.g. fn(n: int) int { n * 2 }
+func (p *Parser) parseFuncLiteral() ast.Expression {
+	fn := &ast.FuncLiteral{Token: p.curToken}
+	if !p.expectNext(token.LPAREN, "Expected '('") {
+		return nil
+	}
+	fn.Params = p.parseFuncParams()
+
+	if p.peekToken.Type != token.LBRACE {
+		p.nextToken()
+		if fn.ReturnType = p.parseType(); fn.ReturnType == nil {
+			p.Diagnostics.Error(p.curToken, "Expected return type or '{'")
+			return nil
+		}
+	}
+
+	if !p.expectNext(token.LBRACE, "Expected '{'") {
+		return nil
+	}
+	fn.Body = p.parseBlockStatement()
+	return fn
+}
+
+// parseFuncParams parses the parameters of a function, separated by commas.
+// The last parameter may be followed by a comma, e.g. when each parameter is
+// on its own line.
 func (p *Parser) parseFuncParams() []*ast.Parameter {
 	var params []*ast.Parameter
-	if p.peekToken.Type == token.RPAREN {
+	for p.peekToken.Type != token.RPAREN && p.peekToken.Type != token.EOF {
 		p.nextToken()
-		return params
+		if n := len(params); n > 0 && params[n-1].Variadic {
+			p.Diagnostics.Error(params[n-1].Token, "Variadic parameter must be the last parameter")
+		}
+		params = append(params, p.parseParam())
+		if p.peekToken.Type != token.RPAREN {
+			p.expectNext(token.COMMA, "Expected ',' or ')' after parameter")
+		}
 	}
-	for {
+	p.expectNext(token.RPAREN, "Expected ')'")
+	return params
+}
+
+// parseParam parses a parameter, name: type. The name of optional parameters
+// is followed by ?, e.g. id?: string, and the type of variadic parameters is
+// preceded by ..., e.g. items: ...string.
+func (p *Parser) parseParam() *ast.Parameter {
+	param := &ast.Parameter{Token: p.curToken, Name: p.curToken.Literal}
+	if p.curToken.Type != token.IDENT {
+		p.Diagnostics.Error(p.curToken, "Expected parameter name")
+	}
+	if p.peekToken.Type == token.QUESTION {
 		p.nextToken()
-		param := &ast.Parameter{Name: p.curToken.Literal}
+		param.Optional = true
+	}
+	switch p.peekToken.Type {
+	case token.COLON:
+		p.nextToken()
+	case token.COMMA, token.RPAREN, token.ASSIGN, token.EOF:
+		// Reported as a missing type
+	default:
+		p.Diagnostics.Error(p.peekToken, "Expected ':' after parameter name")
+	}
+	if p.peekToken.Type == token.ELLIPSIS {
 		p.nextToken()
-		param.Type = p.parseType()
-		params = append(params, param)
-		if p.peekToken.Type != token.COMMA {
-			break
+		param.Variadic = true
+	}
+
+	switch p.peekToken.Type {
+	case token.COMMA, token.RPAREN, token.ASSIGN, token.EOF:
+		p.Diagnostics.Error(p.peekToken, "Expected parameter type")
+	default:
+		p.nextToken()
+		if param.Type = p.parseType(); param.Type == nil {
+			p.Diagnostics.Error(p.curToken, "Expected parameter type")
 		}
+	}
+
+	if p.peekToken.Type == token.ASSIGN {
+		p.nextToken()
 		p.nextToken()
+		param.Default = p.parseExpression(LOWEST)
 	}
-	p.expectNext(token.RPAREN, "Expected ')'")
-	return params
+	if param.Variadic && (param.Optional || param.Default != nil) {
+		p.Diagnostics.Error(param.Token, "Variadic parameter cannot be optional or have a default value")
+	}
+	return param
 }
 
 func (p *Parser) parseReturnStatement() *ast.ReturnStatement {
@@ -267,17 +424,135 @@ func (p *Parser) parseLoopStatement() *ast.Loop {
 	return loop
 }
 
-func (p *Parser) parseForStatement() *ast.For {
+// parseForStatement parses the loops starting with for: conditional loops,
+// C-style loops and loops over the items of a collection or range.
+func (p *Parser) parseForStatement() ast.Node {
+	p.nextToken()
+
+	if p.curToken.Type == token.IDENT && (p.peekToken.Type == token.IN || p.peekToken.Type == token.COMMA) {
+		return p.parseForInStatement()
+	}
+
 	loop := &ast.For{}
+	switch {
+	case p.curToken.Type == token.LET:
+		let := p.parseLetStatement()
+		if let == nil {
+			return nil
+		}
+		loop.Init = let
+		p.nextToken()
+		p.parseForClauses(loop)
+	case p.curToken.Type == token.IDENT && p.peekToken.Type == token.ASSIGN:
+		// The variable of the loop, e.g. for i = 0; ...
+		loop.Init = p.parseLoopVariable()
+		p.nextToken()
+		p.parseForClauses(loop)
+	case p.curToken.Type == token.SEMICOLON:
+		p.parseForClauses(loop)
+	default:
+		loop.Condition = p.parseExpression(LOWEST)
+	}
+
+	p.expectNext(token.LBRACE, "Expected '{'")
+	loop.Body = p.parseBlockStatement()
+	return loop
+}
+
+// parseLoopVariable parses the declaration of the variable of a C-style
+// loop, e.g. i = 0.
+func (p *Parser) parseLoopVariable() *ast.LetStatement {
+	let := &ast.LetStatement{Name: &ast.Identifier{Name: p.curToken.Literal}, Mutable: true}
 	p.nextToken()
-	loop.Condition = p.parseExpression(LOWEST)
+	p.nextToken()
+	let.Value = p.parseExpression(LOWEST)
+	return let
+}
+
+// parseForClauses parses the condition and post statement of a C-style loop
+// following the ';' which ends the init statement.
+func (p *Parser) parseForClauses(loop *ast.For) {
+	if p.curToken.Type != token.SEMICOLON {
+		p.Diagnostics.Error(p.curToken, "Expected ';'")
+		return
+	}
+
+	if p.peekToken.Type != token.SEMICOLON {
+		p.nextToken()
+		loop.Condition = p.parseExpression(LOWEST)
+	}
+	if !p.expectNext(token.SEMICOLON, "Expected ';'") {
+		return
+	}
+
+	if p.peekToken.Type != token.LBRACE {
+		p.nextToken()
+		if post := p.parseAssignStatement(p.parseExpression(LOWEST)); post != nil {
+			loop.Post = post
+		}
+	}
+}
+
+// parseAssignStatement parses the assignment to target, which is followed by
+// the '=' or compound assignment operator, e.g. '+=', at the peek token.
+func (p *Parser) parseAssignStatement(target ast.Expression) *ast.AssignStatement {
+	if !p.peekToken.Type.IsAssignment() {
+		p.Diagnostics.Error(p.peekToken, "Expected '='")
+		return nil
+	}
+	p.nextToken()
+
+	switch target.(type) {
+	case *ast.Identifier, *ast.MemberExpression, *ast.IndexExpression:
+	default:
+		p.Diagnostics.Error(p.curToken, "Expected a variable, field or index to assign to")
+	}
+
+	stmt := &ast.AssignStatement{Token: p.curToken, Target: target, Operator: p.curToken.Literal}
+	p.nextToken()
+	stmt.Value = p.parseExpression(LOWEST)
+	return stmt
+}
+
+// parseForInStatement parses a loop over the items of a collection or range
+// starting at the name of the item, e.g. for x in xs {}.
+func (p *Parser) parseForInStatement() ast.Node {
+	loop := &ast.ForIn{}
+	var ok bool
+	loop.Key, loop.Item, loop.Iterable, ok = p.parseForIn()
+	if !ok {
+		return nil
+	}
 	p.expectNext(token.LBRACE, "Expected '{'")
 	loop.Body = p.parseBlockStatement()
 	return loop
 }
 
+// parseForIn parses the names and iterable of a loop over a collection,
+// e.g. x in xs or key, value in m, starting at the current name.
+func (p *Parser) parseForIn() (key, item *ast.Identifier, iterable ast.Expression, ok bool) {
+	item = &ast.Identifier{Name: p.curToken.Literal}
+	if p.peekToken.Type == token.COMMA {
+		p.nextToken()
+		if !p.expectNext(token.IDENT, "Expected name") {
+			return nil, nil, nil, false
+		}
+		key, item = item, &ast.Identifier{Name: p.curToken.Literal}
+	}
+
+	if !p.expectNext(token.IN, "Expected 'in'") {
+		return nil, nil, nil, false
+	}
+	p.nextToken()
+	iterable = p.parseExpression(LOWEST)
+	if _, isRange := iterable.(*ast.RangeExpression); isRange && key != nil {
+		p.Diagnostics.Error(p.curToken, "Expected a single name when looping over a range")
+	}
+	return key, item, iterable, true
+}
+
 func (p *Parser) parseEnum() *ast.Enum {
-	enum := &ast.Enum{}
+	enum := &ast.Enum{Doc: p.docComment()}
 	p.expectNext(token.IDENT, "Expected name")
 	enum.Name = p.curToken.Literal
 	p.expectNext(token.LBRACE, "Expected '{'")
@@ -285,7 +560,7 @@ func (p *Parser) parseEnum() *ast.Enum {
 	var curInt int64
 	for p.peekToken.Type != token.RBRACE && p.peekToken.Type != token.EOF {
 		p.nextToken()
-		m := &ast.EnumMember{Name: p.curToken.Literal}
+		m := &ast.EnumMember{Name: p.curToken.Literal, Doc: p.docComment()}
 		if p.peekToken.Type == token.ASSIGN {
 			p.nextToken()
 			p.nextToken()
@@ -306,8 +581,9 @@ func (p *Parser) parseEnum() *ast.Enum {
 }
 
 func (p *Parser) parseUnion() *ast.Union {
-	u := &ast.Union{}
+	u := &ast.Union{Doc: p.docComment()}
 	p.expectNext(token.IDENT, "Expected name")
+	u.Token = p.curToken
 	u.Name = p.curToken.Literal
 
 	if p.peekToken.Type == token.LANGLE {
@@ -318,7 +594,7 @@ func (p *Parser) parseUnion() *ast.Union {
 	p.expectNext(token.LBRACE, "Expected '{'")
 	for p.peekToken.Type != token.RBRACE && p.peekToken.Type != token.EOF {
 		p.nextToken()
-		f := &ast.UnionField{Name: p.curToken.Literal}
+		f := &ast.UnionField{Name: p.curToken.Literal, Doc: p.docComment()}
 		if p.peekToken.Type == token.LPAREN {
 			p.nextToken()
 			p.nextToken()
@@ -335,7 +611,7 @@ func (p *Parser) parseUnion() *ast.Union {
 }
 
 func (p *Parser) parseStruct() *ast.Struct {
-	u := &ast.Struct{}
+	u := &ast.Struct{Doc: p.docComment()}
 	p.expectNext(token.IDENT, "Expected name")
 	u.Name = p.curToken.Literal
 
@@ -349,7 +625,15 @@ func (p *Parser) parseStruct() *ast.Struct {
 	for p.peekToken.Type != token.RBRACE && p.peekToken.Type != token.EOF {
 		p.nextToken()
 
-		f := &ast.StructField{Name: p.curToken.Literal}
+		if p.curToken.Type == token.FUNC {
+			if fn := p.parseFunc(); fn != nil {
+				fn.Receiver = &ast.TypeIdentifier{Name: u.Name, Parameters: u.Params}
+				u.Methods = append(u.Methods, fn)
+			}
+			continue
+		}
+
+		f := &ast.StructField{Name: p.curToken.Literal, Doc: p.docComment()}
 		p.expectNext(token.COLON, "Expected ':'")
 		p.nextToken()
 
@@ -365,16 +649,62 @@ func (p *Parser) parseStruct() *ast.Struct {
 	return u
 }
 
+// parseInterface parses an interface, whose methods are separated by commas
+// or new lines, e.g. interface Printer { write(line: string) }. Methods may
+// be preceded by fn.
+func (p *Parser) parseInterface() *ast.Interface {
+	i := &ast.Interface{Doc: p.docComment()}
+	if !p.expectNext(token.IDENT, "Expected name") {
+		return nil
+	}
+	i.Name = p.curToken.Literal
+
+	if !p.expectNext(token.LBRACE, "Expected '{'") {
+		return nil
+	}
+
+	for p.peekToken.Type != token.RBRACE && p.peekToken.Type != token.EOF {
+		p.nextToken()
+
+		m := &ast.Func{Doc: p.docComment()}
+		if p.curToken.Type == token.FUNC {
+			p.nextToken()
+		}
+		if p.curToken.Type != token.IDENT {
+			p.Diagnostics.Error(p.curToken, "Expected method name")
+			continue
+		}
+		m.Token = p.curToken
+		m.Name = p.curToken.Literal
+
+		if !p.expectNext(token.LPAREN, "Expected '('") {
+			continue
+		}
+		m.Params = p.parseFuncParams()
+		m.ReturnType = p.parseResultType()
+		i.Methods = append(i.Methods, m)
+
+		if p.peekToken.Type == token.COMMA {
+			p.nextToken()
+		}
+	}
+
+	p.expectNext(token.RBRACE, "Expected '}'")
+	return i
+}
+
 // Types
 
 func (p *Parser) parseType() ast.Type {
 	switch p.curToken.Type {
 	case token.LBRACE:
 		return p.parseStructBody()
-	case token.TYPE_INT, token.TYPE_BOOL, token.TYPE_STRING:
+	case token.TYPE_INT, token.TYPE_FLOAT, token.TYPE_CHAR, token.TYPE_BOOL, token.TYPE_STRING:
 		return &ast.TypeLiteral{Type: p.curToken.Literal}
+	case token.FUNC:
+		return p.parseFuncType()
 	case token.IDENT:
-		t := &ast.TypeIdentifier{Name: p.curToken.Literal}
+		t := &ast.TypeIdentifier{Token: p.curToken, Name: p.curToken.Literal}
 		if p.peekToken.Type == token.LANGLE {
 			p.nextToken()
 			t.Parameters = p.parseTypeParameters()
@@ -385,6 +715,44 @@ func (p *Parser) parseType() ast.Type {
 	}
 }
 
+// parseFuncType parses the type of a function, e.g. fn(int, string) bool. The
+// return type is left out for functions which do not return a value, e.g.
+// fn(Event).
+func (p *Parser) parseFuncType() *ast.FuncType {
+	t := &ast.FuncType{}
+	if !p.expectNext(token.LPAREN, "Expected '('") {
+		return t
+	}
+	for p.peekToken.Type != token.RPAREN && p.peekToken.Type != token.EOF {
+		p.nextToken()
+		param := p.parseType()
+		if param == nil {
+			p.Diagnostics.Error(p.curToken, "Expected type")
+		}
+		t.Params = append(t.Params, param)
+		if p.peekToken.Type == token.COMMA {
+			p.nextToken()
+		}
+	}
+	p.expectNext(token.RPAREN, "Expected ')'")
+	t.ReturnType = p.parseResultType()
+	return t
+}
+
+// parseResultType parses the return type following the parameters of a
+// function without a body, which is on the same line as the parameters, or
+// returns nil if there is none.
+func (p *Parser) parseResultType() ast.Type {
+	switch p.peekToken.Type {
+	case token.IDENT, token.FUNC, token.TYPE_INT, token.TYPE_FLOAT, token.TYPE_CHAR, token.TYPE_BOOL, token.TYPE_STRING:
+		if p.peekToken.Line == p.curToken.Line {
+			p.nextToken()
+			return p.parseType()
+		}
+	}
+	return nil
+}
+
 func (p *Parser) parseTypeParameters() []*ast.TypeParameter {
 	var params []*ast.TypeParameter
 	for p.peekToken.Type != token.RANGLE && p.peekToken.Type != token.EOF {
@@ -402,7 +770,7 @@ func (p *Parser) parseStructBody() *ast.StructBody {
 	body := &ast.StructBody{}
 	for p.peekToken.Type != token.RBRACE && p.peekToken.Type != token.EOF {
 		p.nextToken()
-		field := &ast.StructField{Name: p.curToken.Literal}
+		field := &ast.StructField{Name: p.curToken.Literal, Doc: p.docComment()}
 		p.expectNext(token.COLON, "Expected ':'")
 		p.nextToken()
 		field.Type = p.parseType()
@@ -462,16 +830,131 @@ func (p *Parser) parseExpression(precedence int) ast.Expression {
 }
 
 func (p *Parser) parseIdent() ast.Expression {
-	return &ast.Identifier{Name: p.curToken.Literal}
+	return &ast.Identifier{Token: p.curToken, Name: p.curToken.Literal}
 }
 
 func (p *Parser) parseIntegerLiteral() ast.Expression {
-	val, _ := strconv.ParseInt(p.curToken.Literal, 0, 64)
-	return &ast.IntegerLiteral{Value: val}
+	return p.parseInteger(false)
+}
+
+// parseInteger parses the current INT token, negating it when the literal was
+// preceded by a minus sign so that the full int64 range can be expressed.
+func (p *Parser) parseInteger(negative bool) ast.Expression {
+	lit := p.curToken.Literal
+	base := 10
+	digits := lit
+
+	if len(lit) > 1 && lit[0] == '0' && strings.ContainsRune("xXoObB", rune(lit[1])) {
+		base = 0 // Let strconv handle the prefix and underscores
+	} else if !validUnderscores(lit) {
+		p.Diagnostics.Error(p.curToken, fmt.Sprintf("Invalid integer literal '%s'", lit))
+		return &ast.IntegerLiteral{Signed: negative}
+	} else {
+		digits = strings.ReplaceAll(lit, "_", "")
+	}
+
+	val, err := strconv.ParseUint(digits, base, 64)
+	if err != nil {
+		if errors.Is(err, strconv.ErrRange) {
+			p.Diagnostics.Error(p.curToken, fmt.Sprintf("Integer literal '%s' overflows int", lit))
+		} else {
+			p.Diagnostics.Error(p.curToken, fmt.Sprintf("Invalid integer literal '%s'", lit))
+		}
+		return &ast.IntegerLiteral{Signed: negative}
+	}
+
+	if negative {
+		if val > math.MaxInt64+1 {
+			p.Diagnostics.Error(p.curToken, fmt.Sprintf("Integer literal '-%s' overflows int", lit))
+			return &ast.IntegerLiteral{Signed: true}
+		}
+		return &ast.IntegerLiteral{Value: int64(-val), Signed: true}
+	}
+
+	if val > math.MaxInt64 {
+		p.Diagnostics.Error(p.curToken, fmt.Sprintf("Integer literal '%s' overflows int", lit))
+		return &ast.IntegerLiteral{}
+	}
+	return &ast.IntegerLiteral{Value: int64(val)}
+}
+
+func (p *Parser) parseFloatLiteral() ast.Expression {
+	return p.parseFloat(false)
+}
+
+func (p *Parser) parseFloat(negative bool) ast.Expression {
+	lit := p.curToken.Literal
+	val, err := strconv.ParseFloat(lit, 64)
+	if err != nil {
+		if errors.Is(err, strconv.ErrRange) {
+			p.Diagnostics.Error(p.curToken, fmt.Sprintf("Float literal '%s' overflows float", lit))
+		} else {
+			p.Diagnostics.Error(p.curToken, fmt.Sprintf("Invalid float literal '%s'", lit))
+		}
+		return &ast.FloatLiteral{Signed: negative}
+	}
+
+	if negative {
+		val = -val
+	}
+	return &ast.FloatLiteral{Value: val, Signed: negative}
 }
 
 func (p *Parser) parseStringLiteral() ast.Expression {
-	return &ast.StringLiteral{Value: p.curToken.Literal[1 : len(p.curToken.Literal)-1]}
+	lit := p.curToken.Literal
+	if len(lit) < 2 {
+		return &ast.StringLiteral{}
+	}
+	return &ast.StringLiteral{Value: p.unescape(p.curToken, lit[1:len(lit)-1])}
+}
+
+func (p *Parser) parseCharLiteral() ast.Expression {
+	lit := p.curToken.Literal
+	if len(lit) < 2 {
+		return &ast.CharLiteral{}
+	}
+
+	value := p.unescape(p.curToken, lit[1:len(lit)-1])
+	if utf8.RuneCountInString(value) != 1 {
+		p.Diagnostics.Error(p.curToken, "Char literal must contain exactly one character")
+		return &ast.CharLiteral{}
+	}
+
+	r, _ := utf8.DecodeRuneInString(value)
+	return &ast.CharLiteral{Value: r}
+}
+
+// parseTemplateLiteral parses a backtick string. Template text is raw, so
+// escapes are not decoded. Strings without interpolations are plain strings.
+func (p *Parser) parseTemplateLiteral() ast.Expression {
+	tpl := &ast.TemplateLiteral{}
+	hasExpr := false
+
+	for p.peekToken.Type != token.BACKTICK && p.peekToken.Type != token.EOF {
+		p.nextToken()
+
+		switch p.curToken.Type {
+		case token.TEMPLATE_TEXT:
+			tpl.Parts = append(tpl.Parts, &ast.StringLiteral{Value: p.curToken.Literal})
+		case token.TEMPLATE_EXPR_START:
+			hasExpr = true
+			p.nextToken()
+			tpl.Parts = append(tpl.Parts, p.parseExpression(LOWEST))
+			p.expectNext(token.RBRACE, "Expected '}'")
+		default:
+			p.Diagnostics.Error(p.curToken, "Unexpected token in template string")
+		}
+	}
+	p.expectNext(token.BACKTICK, "Expected '`'")
+
+	if !hasExpr {
+		str := &ast.StringLiteral{}
+		if len(tpl.Parts) > 0 {
+			str.Value = tpl.Parts[0].(*ast.StringLiteral).Value
+		}
+		return str
+	}
+	return tpl
 }
 
 func (p *Parser) parseBoolean() ast.Expression {
@@ -479,6 +962,18 @@ func (p *Parser) parseBoolean() ast.Expression {
 }
 
 func (p *Parser) parseUnaryExpression() ast.Expression {
+	// Fold the sign into numeric literals, e.g. -1 is a literal not a negation
+	if p.curToken.Type == token.MINUS {
+		switch p.peekToken.Type {
+		case token.INT:
+			p.nextToken()
+			return p.parseInteger(true)
+		case token.FLOAT:
+			p.nextToken()
+			return p.parseFloat(true)
+		}
+	}
+
 	expr := &ast.UnaryExpression{Operator: p.curToken.Literal}
 	p.nextToken()
 	expr.Right = p.parseExpression(PREFIX)
@@ -493,15 +988,46 @@ func (p *Parser) parseBinaryExpression(left ast.Expression) ast.Expression {
 	return expr
 }
 
+func (p *Parser) parseRangeExpression(start ast.Expression) ast.Expression {
+	expr := &ast.RangeExpression{Token: p.curToken, Start: start, Inclusive: p.curToken.Type == token.RANGE_INCL}
+	prec := p.curPrecedence()
+	p.nextToken()
+	expr.End = p.parseExpression(prec)
+	return expr
+}
+
 func (p *Parser) parseGroupedExpression() ast.Expression {
 	p.nextToken()
 	expr := p.parseExpression(LOWEST)
+
+	if p.peekToken.Type == token.COMMA {
+		tuple := &ast.TupleExpression{Items: []ast.Expression{expr}}
+		for p.peekToken.Type == token.COMMA {
+			p.nextToken()
+			p.nextToken()
+			tuple.Items = append(tuple.Items, p.parseExpression(LOWEST))
+		}
+		p.expectNext(token.RPAREN, "Expected ')'")
+		return tuple
+	}
+
 	p.expectNext(token.RPAREN, "Expected ')'")
 	return &ast.ParenExpression{Expression: expr}
 }
 
+func (p *Parser) parseTypeConversion() ast.Expression {
+	conv := &ast.TypeConversion{Token: p.curToken, Type: &ast.TypeLiteral{Type: p.curToken.Literal}}
+	if !p.expectNext(token.LPAREN, "Expected '('") {
+		return nil
+	}
+	p.nextToken()
+	conv.Value = p.parseExpression(LOWEST)
+	p.expectNext(token.RPAREN, "Expected ')'")
+	return conv
+}
+
 func (p *Parser) parseCallExpression(fn ast.Expression) ast.Expression {
-	exp := &ast.CallExpression{Function: fn, Arguments: []ast.Expression{}}
+	exp := &ast.CallExpression{Token: p.curToken, Function: fn, Arguments: []ast.Expression{}}
 	if p.peekToken.Type == token.RPAREN {
 		p.nextToken()
 		return exp
@@ -509,12 +1035,65 @@ func (p *Parser) parseCallExpression(fn ast.Expression) ast.Expression {
 
 	p.nextToken()
 
-	exp.Arguments = append(exp.Arguments, p.parseExpression(LOWEST))
+	exp.Arguments = append(exp.Arguments, p.parseArgument())
 	for p.peekToken.Type == token.COMMA {
 		p.nextToken()
 		p.nextToken()
-		exp.Arguments = append(exp.Arguments, p.parseExpression(LOWEST))
+		exp.Arguments = append(exp.Arguments, p.parseArgument())
 	}
 	p.expectNext(token.RPAREN, "Expected ')'")
 	return exp
 }
+
+// parseArgument parses a positional argument, or a labeled argument such as
+// id: x and its punned forms id: and ~id.
+func (p *Parser) parseArgument() ast.Expression {
+	switch {
+	case p.curToken.Type == token.IDENT && p.peekToken.Type == token.COLON:
+		arg := &ast.LabeledArgument{Token: p.curToken, Label: p.curToken.Literal}
+		p.nextToken()
+		if p.peekToken.Type == token.COMMA || p.peekToken.Type == token.RPAREN {
+			arg.Value = &ast.Identifier{Token: arg.Token, Name: arg.Label}
+			arg.Punned = true
+			return arg
+		}
+		p.nextToken()
+		arg.Value = p.parseExpression(LOWEST)
+		return arg
+
+	case p.curToken.Type == token.BITWISE_NOT:
+		if !p.expectNext(token.IDENT, "Expected name after '~'") {
+			return nil
+		}
+		return &ast.LabeledArgument{
+			Token:  p.curToken,
+			Label:  p.curToken.Literal,
+			Value:  &ast.Identifier{Token: p.curToken, Name: p.curToken.Literal},
+			Punned: true,
+		}
+
+	default:
+		return p.parseExpression(LOWEST)
+	}
+}
+
+func (p *Parser) parseMemberExpression(object ast.Expression) ast.Expression {
+	if !p.expectNext(token.IDENT, "Expected field name after '.'") {
+		return object
+	}
+	return &ast.MemberExpression{Object: object, Property: &ast.Identifier{Name: p.curToken.Literal}}
+}
+
+func (p *Parser) parseIndexExpression(object ast.Expression) ast.Expression {
+	expr := &ast.IndexExpression{Object: object}
+	p.nextToken()
+	expr.Index = p.parseExpression(LOWEST)
+	p.expectNext(token.RBRACKET, "Expected ']'")
+	return expr
+}
+
+// validUnderscores reports whether the underscores in a decimal literal only
+// separate digits, e.g. 1_000 but not 1_ or 1__0.
+func validUnderscores(lit string) bool {
+	return !strings.HasPrefix(lit, "_") && !strings.HasSuffix(lit, "_") && !strings.Contains(lit, "__")
+}
@@ -4,9 +4,12 @@ import (
 	"fmt"
 	"gloss/ast"
 	"gloss/lexer"
+	"gloss/token"
+	"math"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 )
 
 func assertParse(t *testing.T, input string, want ast.SourceFile) {
@@ -19,7 +22,8 @@ func assertParse(t *testing.T, input string, want ast.SourceFile) {
 		fmt.Println(msg.Text)
 	}
 
-	if diff := cmp.Diff(want, got); diff != "" {
+	// Positions are covered by the lexer tests
+	if diff := cmp.Diff(want, got, cmpopts.IgnoreTypes(token.Token{})); diff != "" {
 		t.Errorf("parser.Parse() mismatch (-want +got):\nInput:%s\n%s", input, diff)
 	}
 }
@@ -37,7 +41,7 @@ func TestParseFunc_HelloWorld(t *testing.T) {
 }
 
 func TestParseFunc_WithParams(t *testing.T) {
-	input := `fn print(msg string) {}`
+	input := `fn print(msg: string) {}`
 	want := ast.SourceFile{
 		Declarations: []ast.Node{
 			&ast.Func{
@@ -53,7 +57,7 @@ func TestParseFunc_WithParams(t *testing.T) {
 }
 
 func TestParseFunc_Adder(t *testing.T) {
-	input := `fn add(a int, b int) int {}`
+	input := `fn add(a: int, b: int) int {}`
 	want := ast.SourceFile{
 		Declarations: []ast.Node{
 			&ast.Func{
@@ -70,6 +74,130 @@ func TestParseFunc_Adder(t *testing.T) {
 	assertParse(t, input, want)
 }
 
+func TestParseFunc_OptionalParams(t *testing.T) {
+	input := `fn link(href: string, id?: string, size: int = 1) {}`
+	want := ast.SourceFile{
+		Declarations: []ast.Node{
+			&ast.Func{
+				Name: "link",
+				Params: []*ast.Parameter{
+					{Name: "href", Type: &ast.TypeLiteral{Type: "string"}},
+					{Name: "id", Type: &ast.TypeLiteral{Type: "string"}, Optional: true},
+					{Name: "size", Type: &ast.TypeLiteral{Type: "int"}, Default: &ast.IntegerLiteral{Value: 1}},
+				},
+				Body: &ast.BlockStatement{},
+			},
+		},
+	}
+	assertParse(t, input, want)
+}
+
+func TestParseFunc_ParamForms(t *testing.T) {
+	input := `fn div(
+	id?: string,
+	class: string, count: int,
+	children: ...Element,
+) {}`
+	want := ast.SourceFile{
+		Declarations: []ast.Node{
+			&ast.Func{
+				Name: "div",
+				Params: []*ast.Parameter{
+					{Name: "id", Type: &ast.TypeLiteral{Type: "string"}, Optional: true},
+					{Name: "class", Type: &ast.TypeLiteral{Type: "string"}},
+					{Name: "count", Type: &ast.TypeLiteral{Type: "int"}},
+					{Name: "children", Type: &ast.TypeIdentifier{Name: "Element"}, Variadic: true},
+				},
+				Body: &ast.BlockStatement{},
+			},
+		},
+	}
+	assertParse(t, input, want)
+}
+
+func TestParseFunc_InvalidParams(t *testing.T) {
+	input := `fn a(1: int, b:, c) {}
+fn d(items: ...int, e: int) {}
+fn f(items?: ...int) {}
+fn g(a: int b: int) {}
+fn h(a int, b: int) {}`
+	p := NewParser(lexer.New([]byte(input)))
+	p.Parse()
+
+	var got []string
+	for _, msg := range p.Diagnostics.Messages() {
+		got = append(got, fmt.Sprintf("%d:%d: %s", msg.Line, msg.Column, msg.Text))
+	}
+
+	want := []string{
+		"0:5: Expected parameter name",
+		"0:15: Expected parameter type",
+		"0:18: Expected parameter type",
+		"1:5: Variadic parameter must be the last parameter",
+		"2:5: Variadic parameter cannot be optional or have a default value",
+		"3:12: Expected ',' or ')' after parameter",
+		"4:7: Expected ':' after parameter name",
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("parser.Parse() diagnostics mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestParseFunc_Closures(t *testing.T) {
+	input := `fn adder(by: int) fn(int) int {
+	fn(n: int) int { n + by }
+}
+fn button(onClick: fn(Event)
+	label: string) {}`
+	want := ast.SourceFile{
+		Declarations: []ast.Node{
+			&ast.Func{
+				Name: "adder",
+				Params: []*ast.Parameter{
+					{Name: "by", Type: &ast.TypeLiteral{Type: "int"}},
+				},
+				ReturnType: &ast.FuncType{
+					Params:     []ast.Type{&ast.TypeLiteral{Type: "int"}},
+					ReturnType: &ast.TypeLiteral{Type: "int"},
+				},
+				Body: &ast.BlockStatement{
+					Statements: []ast.Node{
+						&ast.ExpressionStatement{
+							Expression: &ast.FuncLiteral{
+								Params: []*ast.Parameter{
+									{Name: "n", Type: &ast.TypeLiteral{Type: "int"}},
+								},
+								ReturnType: &ast.TypeLiteral{Type: "int"},
+								Body: &ast.BlockStatement{
+									Statements: []ast.Node{
+										&ast.ExpressionStatement{
+											Expression: &ast.BinaryExpression{
+												Left:     &ast.Identifier{Name: "n"},
+												Operator: "+",
+												Right:    &ast.Identifier{Name: "by"},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			&ast.Func{
+				Name: "button",
+				Params: []*ast.Parameter{
+					{Name: "onClick", Type: &ast.FuncType{Params: []ast.Type{&ast.TypeIdentifier{Name: "Event"}}}},
+					{Name: "label", Type: &ast.TypeLiteral{Type: "string"}},
+				},
+				Body: &ast.BlockStatement{},
+			},
+		},
+	}
+	assertParse(t, input, want)
+}
+
 func TestParseFunc_ReturnBinaryExpression(t *testing.T) {
 	input := `fn withreturn() { return 2 + 3 }`
 	want := ast.SourceFile{
@@ -94,7 +222,7 @@ func TestParseFunc_ReturnBinaryExpression(t *testing.T) {
 }
 
 func TestParseFunc_Generic(t *testing.T) {
-	input := `fn join<T>(a T, b T) T { }`
+	input := `fn join<T>(a: T, b: T) T { }`
 	want := ast.SourceFile{
 		Declarations: []ast.Node{
 			&ast.Func{
@@ -112,8 +240,111 @@ func TestParseFunc_Generic(t *testing.T) {
 	assertParse(t, input, want)
 }
 
+func TestParseFunc_DocComment(t *testing.T) {
+	input := `
+	// Not documentation
+	/// Adds two numbers.
+	/// Overflow wraps.
+	fn add() {}`
+	want := ast.SourceFile{
+		Declarations: []ast.Node{
+			&ast.Func{
+				Doc:  "Adds two numbers.\nOverflow wraps.",
+				Name: "add",
+				Body: &ast.BlockStatement{},
+			},
+		},
+	}
+	assertParse(t, input, want)
+}
+
 // --- Let Statement Tests ---
 
+func TestParseFunc_ExpressionStatements(t *testing.T) {
+	input := `fn main() {
+	io.println("hi")
+	double(2)
+}
+fn double(x: int) int { x * 2 }`
+	want := ast.SourceFile{
+		Declarations: []ast.Node{
+			&ast.Func{
+				Name: "main",
+				Body: &ast.BlockStatement{
+					Statements: []ast.Node{
+						&ast.ExpressionStatement{
+							Expression: &ast.CallExpression{
+								Function: &ast.MemberExpression{
+									Object:   &ast.Identifier{Name: "io"},
+									Property: &ast.Identifier{Name: "println"},
+								},
+								Arguments: []ast.Expression{&ast.StringLiteral{Value: "hi"}},
+							},
+						},
+						&ast.ExpressionStatement{
+							Expression: &ast.CallExpression{
+								Function:  &ast.Identifier{Name: "double"},
+								Arguments: []ast.Expression{&ast.IntegerLiteral{Value: 2}},
+							},
+						},
+					},
+				},
+			},
+			&ast.Func{
+				Name: "double",
+				Params: []*ast.Parameter{
+					{Name: "x", Type: &ast.TypeLiteral{Type: "int"}},
+				},
+				ReturnType: &ast.TypeLiteral{Type: "int"},
+				Body: &ast.BlockStatement{
+					Statements: []ast.Node{
+						&ast.ExpressionStatement{
+							Expression: &ast.BinaryExpression{
+								Left:     &ast.Identifier{Name: "x"},
+								Right:    &ast.IntegerLiteral{Value: 2},
+								Operator: "*",
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	assertParse(t, input, want)
+}
+
+func TestParseCall_LabeledArguments(t *testing.T) {
+	input := `let a = f(1, id: x, class:, ~title)`
+	want := ast.SourceFile{
+		Declarations: []ast.Node{
+			&ast.LetStatement{
+				Name: &ast.Identifier{Name: "a"},
+				Value: &ast.CallExpression{
+					Function: &ast.Identifier{Name: "f"},
+					Arguments: []ast.Expression{
+						&ast.IntegerLiteral{Value: 1},
+						&ast.LabeledArgument{
+							Label: "id",
+							Value: &ast.Identifier{Name: "x"},
+						},
+						&ast.LabeledArgument{
+							Label:  "class",
+							Value:  &ast.Identifier{Name: "class"},
+							Punned: true,
+						},
+						&ast.LabeledArgument{
+							Label:  "title",
+							Value:  &ast.Identifier{Name: "title"},
+							Punned: true,
+						},
+					},
+				},
+			},
+		},
+	}
+	assertParse(t, input, want)
+}
+
 func TestParseLet_String(t *testing.T) {
 	input := `let msg = "hello world"`
 	want := ast.SourceFile{
@@ -127,6 +358,120 @@ func TestParseLet_String(t *testing.T) {
 	assertParse(t, input, want)
 }
 
+func TestParseLet_StringEscapes(t *testing.T) {
+	input := `let msg = "a\nb\t\"c\" \\ \u{1F600}"`
+	want := ast.SourceFile{
+		Declarations: []ast.Node{
+			&ast.LetStatement{
+				Name:  &ast.Identifier{Name: "msg"},
+				Value: &ast.StringLiteral{Value: "a\nb\t\"c\" \\ 😀"},
+			},
+		},
+	}
+	assertParse(t, input, want)
+}
+
+func TestParseLet_InvalidStringEscapes(t *testing.T) {
+	input := `let msg = "\q \u{110000} \u00e9"`
+	p := NewParser(lexer.New([]byte(input)))
+	p.Parse()
+
+	var got []string
+	for _, msg := range p.Diagnostics.Messages() {
+		got = append(got, fmt.Sprintf("%d: %s", msg.Column, msg.Text))
+	}
+
+	want := []string{
+		"11: Invalid escape sequence '\\q'",
+		"14: Invalid unicode escape '\\u{110000}'",
+		"25: Invalid unicode escape '\\u'",
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("parser.Parse() diagnostics mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestParseLet_InvalidStringEscapesAfterMultibyte(t *testing.T) {
+	input := `let msg = "é😀 \q"`
+	p := NewParser(lexer.New([]byte(input)))
+	p.Parse()
+
+	var got []string
+	for _, msg := range p.Diagnostics.Messages() {
+		got = append(got, fmt.Sprintf("%d: %s", msg.Column, msg.Text))
+	}
+
+	// Columns count characters rather than bytes
+	want := []string{
+		"14: Invalid escape sequence '\\q'",
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("parser.Parse() diagnostics mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestParseLet_TemplateStrings(t *testing.T) {
+	input := "let a = `raw \\n`\nlet b = `Hello ${name}!`"
+	want := ast.SourceFile{
+		Declarations: []ast.Node{
+			&ast.LetStatement{
+				Name:  &ast.Identifier{Name: "a"},
+				Value: &ast.StringLiteral{Value: "raw \\n"},
+			},
+			&ast.LetStatement{
+				Name: &ast.Identifier{Name: "b"},
+				Value: &ast.TemplateLiteral{
+					Parts: []ast.Expression{
+						&ast.StringLiteral{Value: "Hello "},
+						&ast.Identifier{Name: "name"},
+						&ast.StringLiteral{Value: "!"},
+					},
+				},
+			},
+		},
+	}
+	assertParse(t, input, want)
+}
+
+func TestParseLet_Chars(t *testing.T) {
+	input := `
+	let a = 'a'
+	let b = '\u{e9}'
+	let c = int(a) == 97
+	let d = 'ab'
+	`
+	want := ast.SourceFile{
+		Declarations: []ast.Node{
+			&ast.LetStatement{
+				Name:  &ast.Identifier{Name: "a"},
+				Value: &ast.CharLiteral{Value: 'a'},
+			},
+			&ast.LetStatement{
+				Name:  &ast.Identifier{Name: "b"},
+				Value: &ast.CharLiteral{Value: 'é'},
+			},
+			&ast.LetStatement{
+				Name: &ast.Identifier{Name: "c"},
+				Value: &ast.BinaryExpression{
+					Left: &ast.TypeConversion{
+						Type:  &ast.TypeLiteral{Type: "int"},
+						Value: &ast.Identifier{Name: "a"},
+					},
+					Right:    &ast.IntegerLiteral{Value: 97},
+					Operator: "==",
+				},
+			},
+			&ast.LetStatement{
+				Name:  &ast.Identifier{Name: "d"},
+				Value: &ast.CharLiteral{},
+			},
+		},
+	}
+	assertParse(t, input, want)
+}
+
 func TestParseLet_ComplexExpression(t *testing.T) {
 	input := `let zero = (10-5)*0`
 	want := ast.SourceFile{
@@ -150,8 +495,137 @@ func TestParseLet_ComplexExpression(t *testing.T) {
 	assertParse(t, input, want)
 }
 
+func TestParseLet_NumericLiterals(t *testing.T) {
+	input := `
+	let a = -42
+	let b = 0xff
+	let c = 0o17 + 0b1_01
+	let d = -2.5e3
+	let e = -9223372036854775808
+	let f = -(1)
+	`
+	want := ast.SourceFile{
+		Declarations: []ast.Node{
+			&ast.LetStatement{
+				Name:  &ast.Identifier{Name: "a"},
+				Value: &ast.IntegerLiteral{Value: -42, Signed: true},
+			},
+			&ast.LetStatement{
+				Name:  &ast.Identifier{Name: "b"},
+				Value: &ast.IntegerLiteral{Value: 255},
+			},
+			&ast.LetStatement{
+				Name: &ast.Identifier{Name: "c"},
+				Value: &ast.BinaryExpression{
+					Left:     &ast.IntegerLiteral{Value: 15},
+					Right:    &ast.IntegerLiteral{Value: 5},
+					Operator: "+",
+				},
+			},
+			&ast.LetStatement{
+				Name:  &ast.Identifier{Name: "d"},
+				Value: &ast.FloatLiteral{Value: -2500, Signed: true},
+			},
+			&ast.LetStatement{
+				Name:  &ast.Identifier{Name: "e"},
+				Value: &ast.IntegerLiteral{Value: math.MinInt64, Signed: true},
+			},
+			&ast.LetStatement{
+				Name: &ast.Identifier{Name: "f"},
+				Value: &ast.UnaryExpression{
+					Operator: "-",
+					Right:    &ast.ParenExpression{Expression: &ast.IntegerLiteral{Value: 1}},
+				},
+			},
+		},
+	}
+	assertParse(t, input, want)
+}
+
+func TestParseLet_InvalidNumericLiterals(t *testing.T) {
+	input := `
+	let a = 9223372036854775808
+	let b = 1__0
+	let c = 0b102
+	let d = 1e400
+	`
+	p := NewParser(lexer.New([]byte(input)))
+	p.Parse()
+
+	var got []string
+	for _, msg := range p.Diagnostics.Messages() {
+		got = append(got, msg.Text)
+	}
+
+	want := []string{
+		"Integer literal '9223372036854775808' overflows int",
+		"Invalid integer literal '1__0'",
+		"Invalid integer literal '0b102'",
+		"Float literal '1e400' overflows float",
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("parser.Parse() diagnostics mismatch (-want +got):\n%s", diff)
+	}
+}
+
 // --- Enum Tests ---
 
+func TestParseLet_Types(t *testing.T) {
+	input := `let a: int = 1
+let mut b: Shape
+let c: Option<T> = none
+let d: float`
+	want := ast.SourceFile{
+		Declarations: []ast.Node{
+			&ast.LetStatement{
+				Name:  &ast.Identifier{Name: "a"},
+				Type:  &ast.TypeLiteral{Type: "int"},
+				Value: &ast.IntegerLiteral{Value: 1},
+			},
+			&ast.LetStatement{
+				Name:    &ast.Identifier{Name: "b"},
+				Mutable: true,
+				Type:    &ast.TypeIdentifier{Name: "Shape"},
+			},
+			&ast.LetStatement{
+				Name: &ast.Identifier{Name: "c"},
+				Type: &ast.TypeIdentifier{
+					Name:       "Option",
+					Parameters: []*ast.TypeParameter{{Name: "T"}},
+				},
+				Value: &ast.Identifier{Name: "none"},
+			},
+			&ast.LetStatement{
+				Name: &ast.Identifier{Name: "d"},
+				Type: &ast.TypeLiteral{Type: "float"},
+			},
+		},
+	}
+	assertParse(t, input, want)
+}
+
+func TestParseLet_InvalidTypes(t *testing.T) {
+	input := `let a
+let b: = 1`
+	p := NewParser(lexer.New([]byte(input)))
+	p.Parse()
+
+	var got []string
+	for _, msg := range p.Diagnostics.Messages() {
+		got = append(got, fmt.Sprintf("%d:%d: %s", msg.Line, msg.Column, msg.Text))
+	}
+
+	want := []string{
+		"1:0: Expected '='",
+		"1:7: Expected type",
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("parser.Parse() diagnostics mismatch (-want +got):\n%s", diff)
+	}
+}
+
 func TestParseEnum_MixedValues(t *testing.T) {
 	input := `enum Message { Increment = 1, Decrement = "down", Clear, }`
 	want := ast.SourceFile{
@@ -169,6 +643,29 @@ func TestParseEnum_MixedValues(t *testing.T) {
 	assertParse(t, input, want)
 }
 
+func TestParseStruct_DocComments(t *testing.T) {
+	input := `
+	/// A point in space
+	struct Point {
+		/// Horizontal offset
+		x: int,
+		y: int, // trailing comments belong to the next token
+	}`
+	want := ast.SourceFile{
+		Declarations: []ast.Node{
+			&ast.Struct{
+				Doc:  "A point in space",
+				Name: "Point",
+				Fields: []*ast.StructField{
+					{Doc: "Horizontal offset", Name: "x", Type: &ast.TypeLiteral{Type: "int"}},
+					{Name: "y", Type: &ast.TypeLiteral{Type: "int"}},
+				},
+			},
+		},
+	}
+	assertParse(t, input, want)
+}
+
 // --- Union Tests ---
 
 func TestParseUnion_WithStructTypes(t *testing.T) {
@@ -233,6 +730,70 @@ func TestParseStruct_Generic(t *testing.T) {
 	assertParse(t, input, want)
 }
 
+func TestParseStruct_Methods(t *testing.T) {
+	input := `struct Rect {
+	w: int
+	fn area() int {}
+}
+fn scale(by: int) for Rect int {}`
+	rect := &ast.TypeIdentifier{Name: "Rect"}
+	want := ast.SourceFile{
+		Declarations: []ast.Node{
+			&ast.Struct{
+				Name: "Rect",
+				Fields: []*ast.StructField{
+					{Name: "w", Type: &ast.TypeLiteral{Type: "int"}},
+				},
+				Methods: []*ast.Func{
+					{
+						Name:       "area",
+						ReturnType: &ast.TypeLiteral{Type: "int"},
+						Body:       &ast.BlockStatement{},
+						Receiver:   rect,
+					},
+				},
+			},
+			&ast.Func{
+				Name: "scale",
+				Params: []*ast.Parameter{
+					{Name: "by", Type: &ast.TypeLiteral{Type: "int"}},
+				},
+				ReturnType: &ast.TypeLiteral{Type: "int"},
+				Body:       &ast.BlockStatement{},
+				Receiver:   rect,
+			},
+		},
+	}
+	assertParse(t, input, want)
+}
+
+// --- Interface Tests ---
+
+func TestParseInterface(t *testing.T) {
+	input := `interface Printer {
+	write(line: string)
+	fn flush() bool
+	close(), reset()
+}`
+	want := ast.SourceFile{
+		Declarations: []ast.Node{
+			&ast.Interface{
+				Name: "Printer",
+				Methods: []*ast.Func{
+					{
+						Name:   "write",
+						Params: []*ast.Parameter{{Name: "line", Type: &ast.TypeLiteral{Type: "string"}}},
+					},
+					{Name: "flush", ReturnType: &ast.TypeLiteral{Type: "bool"}},
+					{Name: "close"},
+					{Name: "reset"},
+				},
+			},
+		},
+	}
+	assertParse(t, input, want)
+}
+
 // --- If/Else Tests ---
 
 func TestParseIf_ConstantCondition(t *testing.T) {
@@ -368,3 +929,549 @@ func TestParseLoop_For(t *testing.T) {
 	}
 	assertParse(t, input, want)
 }
+
+func TestParseLoop_ForClauses(t *testing.T) {
+	input := `for i = 0; i < 10; i = i + 1 {}
+for let j = 10; j > 0; {}
+for ;; {}`
+	want := ast.SourceFile{
+		Declarations: []ast.Node{
+			&ast.For{
+				Init: &ast.LetStatement{
+					Name:    &ast.Identifier{Name: "i"},
+					Mutable: true,
+					Value:   &ast.IntegerLiteral{Value: 0},
+				},
+				Condition: &ast.BinaryExpression{
+					Left:     &ast.Identifier{Name: "i"},
+					Right:    &ast.IntegerLiteral{Value: 10},
+					Operator: "<",
+				},
+				Post: &ast.AssignStatement{
+					Target:   &ast.Identifier{Name: "i"},
+					Operator: "=",
+					Value: &ast.BinaryExpression{
+						Left:     &ast.Identifier{Name: "i"},
+						Right:    &ast.IntegerLiteral{Value: 1},
+						Operator: "+",
+					},
+				},
+				Body: &ast.BlockStatement{},
+			},
+			&ast.For{
+				Init: &ast.LetStatement{
+					Name:  &ast.Identifier{Name: "j"},
+					Value: &ast.IntegerLiteral{Value: 10},
+				},
+				Condition: &ast.BinaryExpression{
+					Left:     &ast.Identifier{Name: "j"},
+					Right:    &ast.IntegerLiteral{Value: 0},
+					Operator: ">",
+				},
+				Body: &ast.BlockStatement{},
+			},
+			&ast.For{
+				Body: &ast.BlockStatement{},
+			},
+		},
+	}
+	assertParse(t, input, want)
+}
+
+func TestParseLoop_ForIn(t *testing.T) {
+	input := `for x in xs { break }
+for key, value in m {}
+for i in 0..n + 1 {}
+for i in 1..=10 {}`
+	want := ast.SourceFile{
+		Declarations: []ast.Node{
+			&ast.ForIn{
+				Item:     &ast.Identifier{Name: "x"},
+				Iterable: &ast.Identifier{Name: "xs"},
+				Body: &ast.BlockStatement{
+					Statements: []ast.Node{&ast.BreakStatement{}},
+				},
+			},
+			&ast.ForIn{
+				Key:      &ast.Identifier{Name: "key"},
+				Item:     &ast.Identifier{Name: "value"},
+				Iterable: &ast.Identifier{Name: "m"},
+				Body:     &ast.BlockStatement{},
+			},
+			&ast.ForIn{
+				Item: &ast.Identifier{Name: "i"},
+				Iterable: &ast.RangeExpression{
+					Start: &ast.IntegerLiteral{Value: 0},
+					End: &ast.BinaryExpression{
+						Left:     &ast.Identifier{Name: "n"},
+						Right:    &ast.IntegerLiteral{Value: 1},
+						Operator: "+",
+					},
+				},
+				Body: &ast.BlockStatement{},
+			},
+			&ast.ForIn{
+				Item: &ast.Identifier{Name: "i"},
+				Iterable: &ast.RangeExpression{
+					Start:     &ast.IntegerLiteral{Value: 1},
+					End:       &ast.IntegerLiteral{Value: 10},
+					Inclusive: true,
+				},
+				Body: &ast.BlockStatement{},
+			},
+		},
+	}
+	assertParse(t, input, want)
+}
+
+func TestParseAssignment(t *testing.T) {
+	input := `fn f() {
+	let mut x = 1
+	x = 2
+	x += 3
+	p.x -= 1
+	xs[i] *= 2
+	m["a"].b /= 4
+	x %= 5
+}`
+	want := ast.SourceFile{
+		Declarations: []ast.Node{
+			&ast.Func{
+				Name: "f",
+				Body: &ast.BlockStatement{
+					Statements: []ast.Node{
+						&ast.LetStatement{
+							Name:    &ast.Identifier{Name: "x"},
+							Mutable: true,
+							Value:   &ast.IntegerLiteral{Value: 1},
+						},
+						&ast.AssignStatement{
+							Target:   &ast.Identifier{Name: "x"},
+							Operator: "=",
+							Value:    &ast.IntegerLiteral{Value: 2},
+						},
+						&ast.AssignStatement{
+							Target:   &ast.Identifier{Name: "x"},
+							Operator: "+=",
+							Value:    &ast.IntegerLiteral{Value: 3},
+						},
+						&ast.AssignStatement{
+							Target: &ast.MemberExpression{
+								Object:   &ast.Identifier{Name: "p"},
+								Property: &ast.Identifier{Name: "x"},
+							},
+							Operator: "-=",
+							Value:    &ast.IntegerLiteral{Value: 1},
+						},
+						&ast.AssignStatement{
+							Target: &ast.IndexExpression{
+								Object: &ast.Identifier{Name: "xs"},
+								Index:  &ast.Identifier{Name: "i"},
+							},
+							Operator: "*=",
+							Value:    &ast.IntegerLiteral{Value: 2},
+						},
+						&ast.AssignStatement{
+							Target: &ast.MemberExpression{
+								Object: &ast.IndexExpression{
+									Object: &ast.Identifier{Name: "m"},
+									Index:  &ast.StringLiteral{Value: "a"},
+								},
+								Property: &ast.Identifier{Name: "b"},
+							},
+							Operator: "/=",
+							Value:    &ast.IntegerLiteral{Value: 4},
+						},
+						&ast.AssignStatement{
+							Target:   &ast.Identifier{Name: "x"},
+							Operator: "%=",
+							Value:    &ast.IntegerLiteral{Value: 5},
+						},
+					},
+				},
+			},
+		},
+	}
+	assertParse(t, input, want)
+}
+
+func TestParseAssignment_InvalidTarget(t *testing.T) {
+	input := `fn f() {
+	f() = 1
+	x + 1 += 2
+}`
+	p := NewParser(lexer.New([]byte(input)))
+	p.Parse()
+
+	var got []string
+	for _, msg := range p.Diagnostics.Messages() {
+		got = append(got, fmt.Sprintf("%d:%d: %s", msg.Line, msg.Column, msg.Text))
+	}
+
+	want := []string{
+		"1:5: Expected a variable, field or index to assign to",
+		"2:7: Expected a variable, field or index to assign to",
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("parser.Parse() diagnostics mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestParseLoop_InvalidForIn(t *testing.T) {
+	input := `for i, x in 0..10 {}
+for i = 0; i < 10; i + 1 {}`
+	p := NewParser(lexer.New([]byte(input)))
+	p.Parse()
+
+	var got []string
+	for _, msg := range p.Diagnostics.Messages() {
+		got = append(got, fmt.Sprintf("%d:%d: %s", msg.Line, msg.Column, msg.Text))
+	}
+
+	want := []string{
+		"0:15: Expected a single name when looping over a range",
+		"1:25: Expected '='",
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("parser.Parse() diagnostics mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// --- Element Tests ---
+
+func TestParseElement_Attributes(t *testing.T) {
+	input := `let a = <input id="name" value={value} disabled {...props} />`
+	want := ast.SourceFile{
+		Declarations: []ast.Node{
+			&ast.LetStatement{
+				Name: &ast.Identifier{Name: "a"},
+				Value: &ast.Element{
+					Name: "input",
+					Attributes: []*ast.Attribute{
+						{Name: "id", Value: &ast.StringLiteral{Value: "name"}},
+						{Name: "value", Value: &ast.Identifier{Name: "value"}},
+						{Name: "disabled"},
+						{Spread: true, Value: &ast.Identifier{Name: "props"}},
+					},
+				},
+			},
+		},
+	}
+	assertParse(t, input, want)
+}
+
+func TestParseElement_Children(t *testing.T) {
+	input := `fn App() Node {
+	return <div>
+		Hello, {name}!
+		<p>
+			Welcome
+			back
+		</p>
+		{}
+	</div>
+}`
+	want := ast.SourceFile{
+		Declarations: []ast.Node{
+			&ast.Func{
+				Name:       "App",
+				ReturnType: &ast.TypeIdentifier{Name: "Node"},
+				Body: &ast.BlockStatement{
+					Statements: []ast.Node{
+						&ast.ReturnStatement{
+							Value: &ast.Element{
+								Name: "div",
+								Children: []ast.Expression{
+									&ast.StringLiteral{Value: "Hello, "},
+									&ast.Identifier{Name: "name"},
+									&ast.StringLiteral{Value: "!"},
+									&ast.Element{
+										Name: "p",
+										Children: []ast.Expression{
+											&ast.StringLiteral{Value: "Welcome back"},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	assertParse(t, input, want)
+}
+
+func TestParseElement_Fragment(t *testing.T) {
+	input := `let a = <><b>bold</b> text</>`
+	want := ast.SourceFile{
+		Declarations: []ast.Node{
+			&ast.LetStatement{
+				Name: &ast.Identifier{Name: "a"},
+				Value: &ast.Element{
+					Children: []ast.Expression{
+						&ast.Element{
+							Name:     "b",
+							Children: []ast.Expression{&ast.StringLiteral{Value: "bold"}},
+						},
+						&ast.StringLiteral{Value: " text"},
+					},
+				},
+			},
+		},
+	}
+	assertParse(t, input, want)
+}
+
+func TestParseElement_InvalidSpread(t *testing.T) {
+	input := `let a = <div {props} />`
+	p := NewParser(lexer.New([]byte(input)))
+	p.Parse()
+
+	var got []string
+	for _, msg := range p.Diagnostics.Messages() {
+		got = append(got, fmt.Sprintf("%d: %s", msg.Column, msg.Text))
+	}
+
+	want := []string{
+		"14: Expected '...' in spread attribute",
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("parser.Parse() diagnostics mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestParseElement_ControlFlow(t *testing.T) {
+	input := `let a = <ul>
+	{if time > 0 { Current time: {time} } else if ready { <b/> } else { Waiting }}
+	{for item in items { <li>{item}</li> }}
+</ul>`
+	want := ast.SourceFile{
+		Declarations: []ast.Node{
+			&ast.LetStatement{
+				Name: &ast.Identifier{Name: "a"},
+				Value: &ast.Element{
+					Name: "ul",
+					Children: []ast.Expression{
+						&ast.ElementIf{
+							Condition: &ast.BinaryExpression{
+								Left:     &ast.Identifier{Name: "time"},
+								Right:    &ast.IntegerLiteral{Value: 0},
+								Operator: ">",
+							},
+							Then: []ast.Expression{
+								&ast.StringLiteral{Value: "Current time: "},
+								&ast.Identifier{Name: "time"},
+							},
+							Else: &ast.ElementIf{
+								Condition: &ast.Identifier{Name: "ready"},
+								Then:      []ast.Expression{&ast.Element{Name: "b"}},
+								Else: &ast.Element{
+									Children: []ast.Expression{&ast.StringLiteral{Value: "Waiting"}},
+								},
+							},
+						},
+						&ast.ElementFor{
+							Item:     &ast.Identifier{Name: "item"},
+							Iterable: &ast.Identifier{Name: "items"},
+							Body: []ast.Expression{
+								&ast.Element{
+									Name:     "li",
+									Children: []ast.Expression{&ast.Identifier{Name: "item"}},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	assertParse(t, input, want)
+}
+
+func TestParseElement_Keyed(t *testing.T) {
+	input := `let a = <ul>{for item in items { <li key={item.id} class="item">{item.name}</li> }}</ul>`
+	want := ast.SourceFile{
+		Declarations: []ast.Node{
+			&ast.LetStatement{
+				Name: &ast.Identifier{Name: "a"},
+				Value: &ast.Element{
+					Name: "ul",
+					Children: []ast.Expression{
+						&ast.ElementFor{
+							Item:     &ast.Identifier{Name: "item"},
+							Iterable: &ast.Identifier{Name: "items"},
+							Body: []ast.Expression{
+								&ast.Element{
+									Name: "li",
+									Key: &ast.MemberExpression{
+										Object:   &ast.Identifier{Name: "item"},
+										Property: &ast.Identifier{Name: "id"},
+									},
+									Attributes: []*ast.Attribute{
+										{Name: "class", Value: &ast.StringLiteral{Value: "item"}},
+									},
+									Children: []ast.Expression{
+										&ast.MemberExpression{
+											Object:   &ast.Identifier{Name: "item"},
+											Property: &ast.Identifier{Name: "name"},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	assertParse(t, input, want)
+}
+
+// --- Match Tests ---
+
+func TestParseMatch_Patterns(t *testing.T) {
+	input := `fn f() {
+	match value {
+		0 => zero(),
+		-1.5 => "negative"
+		Color.Red => red()
+		Shape.Circle(r) => r,
+		Some(Ok(_)) => { return 1 }
+		Pair(a, "b") => a,
+		(x, _) => x,
+		other => other,
+		_ => none(),
+	}
+}`
+	want := ast.SourceFile{
+		Declarations: []ast.Node{
+			&ast.Func{
+				Name: "f",
+				Body: &ast.BlockStatement{
+					Statements: []ast.Node{
+						&ast.Match{
+							Subject: &ast.Identifier{Name: "value"},
+							Arms: []*ast.MatchArm{
+								{
+									Pattern: &ast.LiteralPattern{Value: &ast.IntegerLiteral{Value: 0}},
+									Value:   &ast.CallExpression{Function: &ast.Identifier{Name: "zero"}, Arguments: []ast.Expression{}},
+								},
+								{
+									Pattern: &ast.LiteralPattern{Value: &ast.FloatLiteral{Value: -1.5, Signed: true}},
+									Value:   &ast.StringLiteral{Value: "negative"},
+								},
+								{
+									Pattern: &ast.VariantPattern{Type: "Color", Name: "Red"},
+									Value:   &ast.CallExpression{Function: &ast.Identifier{Name: "red"}, Arguments: []ast.Expression{}},
+								},
+								{
+									Pattern: &ast.VariantPattern{Type: "Shape", Name: "Circle", Payload: &ast.BindingPattern{Name: "r"}},
+									Value:   &ast.Identifier{Name: "r"},
+								},
+								{
+									Pattern: &ast.VariantPattern{
+										Name:    "Some",
+										Payload: &ast.VariantPattern{Name: "Ok", Payload: &ast.WildcardPattern{}},
+									},
+									Body: &ast.BlockStatement{
+										Statements: []ast.Node{
+											&ast.ReturnStatement{Value: &ast.IntegerLiteral{Value: 1}},
+										},
+									},
+								},
+								{
+									Pattern: &ast.VariantPattern{
+										Name: "Pair",
+										Payload: &ast.TuplePattern{Items: []ast.Pattern{
+											&ast.BindingPattern{Name: "a"},
+											&ast.LiteralPattern{Value: &ast.StringLiteral{Value: "b"}},
+										}},
+									},
+									Value: &ast.Identifier{Name: "a"},
+								},
+								{
+									Pattern: &ast.TuplePattern{Items: []ast.Pattern{
+										&ast.BindingPattern{Name: "x"},
+										&ast.WildcardPattern{},
+									}},
+									Value: &ast.Identifier{Name: "x"},
+								},
+								{
+									Pattern: &ast.BindingPattern{Name: "other"},
+									Value:   &ast.Identifier{Name: "other"},
+								},
+								{
+									Pattern: &ast.WildcardPattern{},
+									Value:   &ast.CallExpression{Function: &ast.Identifier{Name: "none"}, Arguments: []ast.Expression{}},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	assertParse(t, input, want)
+}
+
+func TestParseMatch_Expression(t *testing.T) {
+	input := `fn name(c: Color) string {
+	return match (c, 1) { (Color.Red, 1) => "red", _ => "other" }
+}`
+	want := ast.SourceFile{
+		Declarations: []ast.Node{
+			&ast.Func{
+				Name:       "name",
+				Params:     []*ast.Parameter{{Name: "c", Type: &ast.TypeIdentifier{Name: "Color"}}},
+				ReturnType: &ast.TypeLiteral{Type: "string"},
+				Body: &ast.BlockStatement{
+					Statements: []ast.Node{
+						&ast.ReturnStatement{
+							Value: &ast.Match{
+								Subject: &ast.TupleExpression{Items: []ast.Expression{
+									&ast.Identifier{Name: "c"},
+									&ast.IntegerLiteral{Value: 1},
+								}},
+								Arms: []*ast.MatchArm{
+									{
+										Pattern: &ast.TuplePattern{Items: []ast.Pattern{
+											&ast.VariantPattern{Type: "Color", Name: "Red"},
+											&ast.LiteralPattern{Value: &ast.IntegerLiteral{Value: 1}},
+										}},
+										Value: &ast.StringLiteral{Value: "red"},
+									},
+									{Pattern: &ast.WildcardPattern{}, Value: &ast.StringLiteral{Value: "other"}},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	assertParse(t, input, want)
+}
+
+func TestParseMatch_InvalidPatterns(t *testing.T) {
+	input := `fn f() { match x { -a => 1, + => 2 } }`
+	p := NewParser(lexer.New([]byte(input)))
+	p.Parse()
+
+	var got []string
+	for _, msg := range p.Diagnostics.Messages() {
+		got = append(got, fmt.Sprintf("%d: %s", msg.Column, msg.Text))
+	}
+
+	want := []string{
+		"20: Expected number after '-' in pattern",
+		"28: Expected pattern",
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("parser.Parse() diagnostics mismatch (-want +got):\n%s", diff)
+	}
+}
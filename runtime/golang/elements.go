@@ -3,7 +3,9 @@ package main
 import (
 	"context"
 	"fmt"
+	"html/template"
 	"io"
+	"iter"
 	// "os"
 	"strings"
 )
@@ -20,11 +22,17 @@ type Attributes []Attribute
 type Renderer interface {
 	Element(tag string, attrs Attributes, children ...Node)
 	Text(content string)
+
+	// Keyed renders a node which is identified by key among the items of a
+	// list, so that renderers which diff or hydrate the output can match it.
+	Keyed(key string, node Node)
 }
 
 type html struct {
 	Context context.Context
 	Writer  io.Writer
+
+	key string // Key of the next element, set by Keyed
 }
 
 func HtmlWriter(ctx context.Context, w io.Writer, rootNode Node) {
@@ -47,6 +55,10 @@ func HtmlString(ctx context.Context, rootNode Node) string {
 
 func (r *html) Element(tag string, attrs Attributes, children ...Node) {
 	fmt.Fprintf(r.Writer, "<%s", tag)
+	if r.key != "" {
+		fmt.Fprintf(r.Writer, ` data-key="%s"`, template.HTMLEscapeString(r.key))
+		r.key = ""
+	}
 	for _, a := range attrs {
 		fmt.Fprintf(r.Writer, " %s", a.Key)
 		// TODO: check for props with no value (e.g. disabled)
@@ -69,6 +81,13 @@ func (r *html) Text(content string) {
 	io.WriteString(r.Writer, content)
 }
 
+// Keyed writes the key as a data-key attribute of the node's element.
+func (r *html) Keyed(key string, node Node) {
+	r.key = key
+	node(r)
+	r.key = ""
+}
+
 func h(tag string, attrs Attributes, children ...Node) Node {
 	return func(r Renderer) {
 		r.Element(tag, attrs, children...)
@@ -85,6 +104,40 @@ func Text(content string) Node {
 	}
 }
 
+// Value renders a value within an element's children. Nodes are rendered as
+// is and any other value as text.
+func Value(v any) Node {
+	switch v := v.(type) {
+	case nil:
+		return nil
+	case Node:
+		return v
+	case string:
+		return Text(v)
+	default:
+		return Text(fmt.Sprint(v))
+	}
+}
+
+// MergeAttributes combines sets of attributes, e.g. those spread into an
+// element with {...props}. Later values replace earlier values of the same
+// key, which keep their original position.
+func MergeAttributes(sets ...Attributes) Attributes {
+	var merged Attributes
+	index := map[string]int{}
+	for _, attrs := range sets {
+		for _, a := range attrs {
+			if i, ok := index[a.Key]; ok {
+				merged[i] = a
+				continue
+			}
+			index[a.Key] = len(merged)
+			merged = append(merged, a)
+		}
+	}
+	return merged
+}
+
 func Fragment(children ...Node) Node {
 	return func(r Renderer) {
 		for _, child := range children {
@@ -95,6 +148,30 @@ func Fragment(children ...Node) Node {
 	}
 }
 
+// List renders each node of a sequence, e.g. the nodes produced by a for
+// loop within element children.
+func List(nodes iter.Seq[Node]) Node {
+	return func(r Renderer) {
+		for node := range nodes {
+			if node != nil {
+				node(r)
+			}
+		}
+	}
+}
+
+// Each renders a sequence of keyed nodes, e.g. the elements produced by a for
+// loop whose body has a key.
+func Each(nodes iter.Seq2[string, Node]) Node {
+	return func(r Renderer) {
+		for key, node := range nodes {
+			if node != nil {
+				r.Keyed(key, node)
+			}
+		}
+	}
+}
+
 func If(cond bool, then Node, otherwise Node) Node {
 	return func(r Renderer) {
 		if cond {
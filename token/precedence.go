@@ -0,0 +1,68 @@
+package token
+
+// Binding powers of binary operators, from loosest to tightest.
+const (
+	_ int = iota
+	LowestPrec
+	RangePrec      // .. or ..=
+	OrPrec         // ||
+	AndPrec        // &&
+	BitwiseOrPrec  // | or ^
+	BitwiseAndPrec // &
+	EqualsPrec     // == or !=
+	ComparePrec    // > or < or >= or <=
+	BitshiftPrec   // << or >>
+	SumPrec        // + or -
+	ProductPrec    // * or / or %
+	PrefixPrec     // -X or !X or ~X
+	CallPrec       // fn(X) or x.y or x[i]
+)
+
+var precedences = [NumTokens]int{
+	// Ranges
+	RANGE:      RangePrec,
+	RANGE_INCL: RangePrec,
+
+	// Equality and Logic
+	EQ:     EqualsPrec,
+	NOT_EQ: EqualsPrec,
+	AND:    AndPrec,
+	OR:     OrPrec,
+
+	// Comparisons
+	LANGLE: ComparePrec,
+	RANGLE: ComparePrec,
+
+	LT:    ComparePrec,
+	GT:    ComparePrec,
+	LT_EQ: ComparePrec,
+	GT_EQ: ComparePrec,
+
+	// Bitwise
+	BITWISE_OR:  BitwiseOrPrec,
+	BITWISE_XOR: BitwiseOrPrec,
+	BITWISE_AND: BitwiseAndPrec,
+	BITSHIFTL:   BitshiftPrec,
+	BITSHIFTR:   BitshiftPrec,
+
+	// Math
+	PLUS:  SumPrec,
+	MINUS: SumPrec,
+	MUL:   ProductPrec,
+	DIV:   ProductPrec,
+	MOD:   ProductPrec,
+
+	// Access / Calls
+	LPAREN:   CallPrec,
+	PERIOD:   CallPrec,
+	LBRACKET: CallPrec,
+}
+
+// Precedence returns the binding power of t when used as a binary operator,
+// or LowestPrec if it is not one.
+func (t TokenType) Precedence() int {
+	if t >= 0 && t < NumTokens && precedences[t] != 0 {
+		return precedences[t]
+	}
+	return LowestPrec
+}
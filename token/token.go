@@ -1,96 +1,197 @@
 package token
 
-type TokenType string
+//go:generate stringer -type=TokenType
+
+type TokenType int
 
 type Token struct {
 	Type    TokenType
 	Literal string
 	Line    int
 	Column  int
-}
 
-// TODO: Convert to iota
+	// Trivia holds the comments which directly precede this token.
+	Trivia []Token
+}
 
+// Token types are grouped so that each class occupies a contiguous range, see
+// IsLiteral, IsOperator, IsAssignment and IsKeyword.
 const (
-	ILLEGAL = "ILLEGAL"
-	EOF     = "EOF"
+	ILLEGAL TokenType = iota
+	EOF
+
+	// Trivia
+	COMMENT
+	DOC_COMMENT
 
 	// Identifiers + literals
-	IDENT  = "IDENT"
-	INT    = "INT"
-	STRING = "STR"
-	BOOL   = "BOOL"
+	IDENT
+	INT
+	FLOAT
+	STRING
+	CHAR
+	BOOL
 
 	// Operators
-	ASSIGN = "ASSIGN"
-	PLUS   = "PLUS"
-	MINUS  = "MINUS"
-	MUL    = "MUL"
-	DIV    = "DIV"
-	MOD    = "MOD"
-	EQ     = "EQ"
-	NOT_EQ = "NOT_EQ"
-	LT     = "LESS_THAN"
-	LT_EQ  = "LESS_THAN_OR_EQUAL"
-	GT     = "GREATER_THAN"
-	GT_EQ  = "GREATER_THAN_OR_EQUAL"
-	AND    = "AND"
-	OR     = "OR"
-
-	BITWISE_OR  = "B_OR"
-	BITWISE_XOR = "B_XOR"
-	BITWISE_NOT = "B_NOT"
-	BITWISE_AND = "B_AND"
-	BITSHIFTL   = "B_SHIFT_L"
-	BITSHIFTR   = "B_SHIFT_R"
+	ASSIGN
+	PLUS_ASSIGN
+	MINUS_ASSIGN
+	MUL_ASSIGN
+	DIV_ASSIGN
+	MOD_ASSIGN
+	PLUS
+	MINUS
+	MUL
+	DIV
+	MOD
+	EQ
+	NOT_EQ
+	LT
+	LT_EQ
+	GT
+	GT_EQ
+	AND
+	OR
+	BANG
+
+	BITWISE_OR
+	BITWISE_XOR
+	BITWISE_NOT
+	BITWISE_AND
+	BITSHIFTL
+	BITSHIFTR
+	RANGE
+	RANGE_INCL
 
 	// Delimiters
-	PERIOD    = "PERIOD"
-	CARET     = "CARET"
-	BANG      = "BANG"
-	QUOTE     = "QUOTE"
-	TICK      = "TICK"
-	BACKTICK  = "BACKTICK"
-	COMMA     = "COMMA"
-	COLON     = "COLON"
-	SEMICOLON = "SEMICOLON"
-	LPAREN    = "LPAREN"
-	RPAREN    = "RPAREN"
-	LBRACE    = "LBRACE"
-	RBRACE    = "RBRACE"
-	LBRACKET  = "LBRACKET"
-	RBRACKET  = "RBRACKET"
-	LANGLE    = "LANGLE"
-	RANGLE    = "RANGLE"
+	PERIOD
+	ELLIPSIS
+	CARET
+	QUOTE
+	BACKTICK
+	COMMA
+	COLON
+	QUESTION
+	SEMICOLON
+	FAT_ARROW
+	LPAREN
+	RPAREN
+	LBRACE
+	RBRACE
+	LBRACKET
+	RBRACKET
+	LANGLE
+	RANGLE
 
 	// Keywords
-	LET                 = "LET"
-	FUNC                = "FUNC"
-	IMPORT              = "IMPORT"
-	ENUM                = "ENUM"
-	UNION               = "UNION"
-	STRUCT              = "STRUCT"
-	EXTERN              = "EXTERN"
-	IF                  = "IF"
-	ELSE                = "ELSE"
-	SWITCH              = "SWITCH"
-	CASE                = "CASE"
-	DEFAULT             = "DEFAULT"
-	FOR                 = "FOR"
-	LOOP                = "LOOP"
-	CONTINUE            = "CONTINUE"
-	BREAK               = "BREAK"
-	RETURN              = "RETURN"
-	ELEMENT_OPEN_START  = "EL_OPEN_START"
-	ELEMENT_OPEN_END    = "EL_OPEN_END"
-	ELEMENT_CLOSE_START = "EL_CLOSE_START"
-	ELEMENT_CLOSE_END   = "EL_CLOSE_END"
-	ELEMENT_VOID_END    = "EL_VOID_END"
-	ELEMENT_IDENT       = "EL_IDENT"
-	ELEMENT_ATTR        = "EL_ATTR"
-	ELEMENT_TEXT        = "EL_TEXT"
-
-	TYPE_STRING = "T_STRING"
-	TYPE_INT    = "T_INT"
-	TYPE_BOOL   = "T_BOOl"
+	LET
+	MUT
+	FUNC
+	IMPORT
+	ENUM
+	UNION
+	STRUCT
+	INTERFACE
+	EXTERN
+	IF
+	ELSE
+	SWITCH
+	CASE
+	DEFAULT
+	MATCH
+	FOR
+	IN
+	LOOP
+	CONTINUE
+	BREAK
+	RETURN
+
+	// Builtin types
+	TYPE_STRING
+	TYPE_INT
+	TYPE_FLOAT
+	TYPE_CHAR
+	TYPE_BOOL
+
+	// Elements
+	ELEMENT_OPEN_START
+	ELEMENT_OPEN_END
+	ELEMENT_CLOSE_START
+	ELEMENT_CLOSE_END
+	ELEMENT_VOID_END
+	ELEMENT_IDENT
+	ELEMENT_ATTR
+	ELEMENT_TEXT
+
+	// Template strings
+	TEMPLATE_TEXT
+	TEMPLATE_EXPR_START
+
+	// NumTokens is the number of token types, it is not a token itself.
+	NumTokens
 )
+
+var keywords = map[string]TokenType{
+	"if":        IF,
+	"else":      ELSE,
+	"for":       FOR,
+	"in":        IN,
+	"loop":      LOOP,
+	"break":     BREAK,
+	"continue":  CONTINUE,
+	"switch":    SWITCH,
+	"case":      CASE,
+	"default":   DEFAULT,
+	"match":     MATCH,
+	"return":    RETURN,
+	"let":       LET,
+	"mut":       MUT,
+	"fn":        FUNC,
+	"enum":      ENUM,
+	"union":     UNION,
+	"struct":    STRUCT,
+	"interface": INTERFACE,
+	"true":      BOOL,
+	"false":     BOOL,
+
+	// Builtin types
+	"bool":   TYPE_BOOL,
+	"string": TYPE_STRING,
+	"int":    TYPE_INT,
+	"float":  TYPE_FLOAT,
+	"char":   TYPE_CHAR,
+}
+
+// Lookup returns the keyword or builtin type for ident, or IDENT if it is an
+// ordinary identifier.
+func Lookup(ident string) TokenType {
+	if t, ok := keywords[ident]; ok {
+		return t
+	}
+	return IDENT
+}
+
+// IsLiteral reports whether t is an identifier or a literal value.
+func (t TokenType) IsLiteral() bool {
+	return t >= IDENT && t <= BOOL
+}
+
+// IsOperator reports whether t is a unary or binary operator.
+func (t TokenType) IsOperator() bool {
+	return t >= ASSIGN && t <= RANGE_INCL
+}
+
+// IsAssignment reports whether t assigns a value, e.g. = or +=.
+func (t TokenType) IsAssignment() bool {
+	return t >= ASSIGN && t <= MOD_ASSIGN
+}
+
+// IsKeyword reports whether t is a reserved word.
+func (t TokenType) IsKeyword() bool {
+	return t >= LET && t <= RETURN
+}
+
+// IsBuiltinType reports whether t names a builtin type.
+func (t TokenType) IsBuiltinType() bool {
+	return t >= TYPE_STRING && t <= TYPE_BOOL
+}
@@ -0,0 +1,93 @@
+package token
+
+import "testing"
+
+func TestString(t *testing.T) {
+	tests := map[TokenType]string{
+		ILLEGAL:   "ILLEGAL",
+		LT_EQ:     "LT_EQ",
+		TYPE_BOOL: "TYPE_BOOL",
+		-1:        "TokenType(-1)",
+	}
+
+	for tt, want := range tests {
+		if got := tt.String(); got != want {
+			t.Errorf("TokenType(%d).String() = %q, want %q", int(tt), got, want)
+		}
+	}
+}
+
+func TestLookup(t *testing.T) {
+	tests := map[string]TokenType{
+		"fn":     FUNC,
+		"true":   BOOL,
+		"string": TYPE_STRING,
+		"char":   TYPE_CHAR,
+		"func":   IDENT,
+		"Let":    IDENT,
+	}
+
+	for ident, want := range tests {
+		if got := Lookup(ident); got != want {
+			t.Errorf("Lookup(%q) = %s, want %s", ident, got, want)
+		}
+	}
+}
+
+func TestClassification(t *testing.T) {
+	tests := []struct {
+		tt                                                  TokenType
+		literal, operator, assignment, keyword, builtinType bool
+	}{
+		{tt: IDENT, literal: true},
+		{tt: BOOL, literal: true},
+		{tt: ASSIGN, operator: true, assignment: true},
+		{tt: MOD_ASSIGN, operator: true, assignment: true},
+		{tt: PLUS, operator: true},
+		{tt: BITSHIFTR, operator: true},
+		{tt: RANGE_INCL, operator: true},
+		{tt: LET, keyword: true},
+		{tt: MUT, keyword: true},
+		{tt: RETURN, keyword: true},
+		{tt: TYPE_INT, builtinType: true},
+		{tt: LPAREN},
+		{tt: ELEMENT_TEXT},
+	}
+
+	for _, test := range tests {
+		if got := test.tt.IsLiteral(); got != test.literal {
+			t.Errorf("%s.IsLiteral() = %v, want %v", test.tt, got, test.literal)
+		}
+		if got := test.tt.IsOperator(); got != test.operator {
+			t.Errorf("%s.IsOperator() = %v, want %v", test.tt, got, test.operator)
+		}
+		if got := test.tt.IsAssignment(); got != test.assignment {
+			t.Errorf("%s.IsAssignment() = %v, want %v", test.tt, got, test.assignment)
+		}
+		if got := test.tt.IsKeyword(); got != test.keyword {
+			t.Errorf("%s.IsKeyword() = %v, want %v", test.tt, got, test.keyword)
+		}
+		if got := test.tt.IsBuiltinType(); got != test.builtinType {
+			t.Errorf("%s.IsBuiltinType() = %v, want %v", test.tt, got, test.builtinType)
+		}
+	}
+}
+
+func TestPrecedence(t *testing.T) {
+	tests := map[TokenType]int{
+		OR:       OrPrec,
+		MUL:      ProductPrec,
+		LANGLE:   ComparePrec,
+		LPAREN:   CallPrec,
+		PERIOD:   CallPrec,
+		LBRACKET: CallPrec,
+		COMMA:    LowestPrec,
+		ILLEGAL:  LowestPrec,
+	}
+
+	for tt, want := range tests {
+		if got := tt.Precedence(); got != want {
+			t.Errorf("%s.Precedence() = %d, want %d", tt, got, want)
+		}
+	}
+}